@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+)
+
+// runBestWindow implements "carbonctl best-window".
+func runBestWindow(args []string) error {
+	fs := flag.NewFlagSet("best-window", flag.ContinueOnError)
+	zones := fs.String("zones", "", "comma-separated zone identifiers, e.g. DE,FR,ES")
+	duration := fs.Duration("duration", 0, "length of the execution window, e.g. 2h")
+	deadline := fs.String("deadline", "", "latest the window may end, RFC3339")
+	output := fs.String("output", "table", "output format: json, yaml, table or csv")
+	failAbove := fs.Float64("fail-above", 0, "exit 1 if the best window's average intensity exceeds this many gCO2eq/kWh (0 disables)")
+	token := tokenFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *duration <= 0 {
+		return fmt.Errorf("--duration is required")
+	}
+	deadlineAt, err := time.Parse(time.RFC3339, *deadline)
+	if err != nil {
+		return fmt.Errorf("parsing --deadline: %w", err)
+	}
+
+	provider, err := newProvider(*token, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	window, err := carbonintensity.BestExecutionWindow(ctx, provider, splitZones(*zones), *duration, deadlineAt)
+	if err != nil {
+		return fmt.Errorf("finding best execution window: %w", err)
+	}
+
+	table := Table{
+		Columns: []string{"zone", "start", "end", "averageIntensity"},
+		Rows: [][]string{{
+			window.Zone,
+			window.Start.Format(time.RFC3339),
+			window.End.Format(time.RFC3339),
+			fmt.Sprintf("%g", window.AverageIntensity),
+		}},
+	}
+	if err := table.Write(os.Stdout, *output); err != nil {
+		return err
+	}
+
+	if *failAbove > 0 && window.AverageIntensity > *failAbove {
+		fmt.Fprintf(os.Stderr, "carbonctl: best window average intensity %g exceeds --fail-above %g\n", window.AverageIntensity, *failAbove)
+		return exitCode(1)
+	}
+	return nil
+}
+
+func splitZones(zones string) []string {
+	var out []string
+	for _, z := range strings.Split(zones, ",") {
+		if z = strings.TrimSpace(z); z != "" {
+			out = append(out, z)
+		}
+	}
+	return out
+}
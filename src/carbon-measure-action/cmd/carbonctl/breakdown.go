@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/electricitymap"
+)
+
+// runBreakdown implements "carbonctl breakdown <subcommand>". Power
+// breakdowns aren't part of the vendor-neutral carbonintensity.Provider
+// interface (only ElectricityMap models them today), so this talks to the
+// electricitymap client directly rather than through a Provider.
+func runBreakdown(args []string) error {
+	if len(args) == 0 || args[0] != "past-range" {
+		return fmt.Errorf("usage: carbonctl breakdown past-range --zone ZONE --start TIME --end TIME")
+	}
+
+	fs := flag.NewFlagSet("breakdown past-range", flag.ContinueOnError)
+	zone := fs.String("zone", "", "zone identifier, e.g. FR")
+	start := fs.String("start", "", "range start, RFC3339")
+	end := fs.String("end", "", "range end, RFC3339 (excluded)")
+	output := fs.String("output", "table", "output format: json, yaml, table or csv")
+	token := tokenFlag(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	resolvedToken, err := requireToken(*token)
+	if err != nil {
+		return err
+	}
+	client := electricitymap.New(resolvedToken)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	data, err := client.PastPowerBreakdownRange(ctx, electricitymap.TypAPIParams{Zone: *zone, Start: *start, End: *end})
+	if err != nil {
+		return fmt.Errorf("fetching past power breakdown range: %w", err)
+	}
+
+	table := Table{Columns: []string{"zone", "datetime", "renewablePercentage", "fossilFreePercentage", "powerConsumptionTotal", "powerProductionTotal"}}
+	for _, pb := range data.History {
+		table.Rows = append(table.Rows, []string{
+			data.Zone,
+			pb.Datetime,
+			fmt.Sprintf("%d", pb.RenewablePercentage),
+			fmt.Sprintf("%d", pb.FossilFreePercentage),
+			fmt.Sprintf("%d", pb.PowerConsumptionTotal),
+			fmt.Sprintf("%d", pb.PowerProductionTotal),
+		})
+	}
+
+	return table.Write(os.Stdout, *output)
+}
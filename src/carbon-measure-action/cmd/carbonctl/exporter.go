@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+)
+
+// configMap is the minimal shape of a Kubernetes ConfigMap manifest, just
+// enough to be `kubectl apply -f`'d or read by an admission webhook without
+// pulling in a full Kubernetes client.
+type configMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   configMapMetadata `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+}
+
+type configMapMetadata struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace"`
+	Annotations map[string]string `yaml:"annotations"`
+}
+
+// zoneReading is the per-zone payload embedded in the ConfigMap's data,
+// carrying both the live reading and a short forecast so a scheduling
+// controller can make a look-ahead decision without its own API calls.
+type zoneReading struct {
+	CarbonIntensity float64                        `json:"carbonIntensity"`
+	UpdatedAt       time.Time                       `json:"updatedAt"`
+	Forecast        []carbonintensity.ForecastPoint `json:"forecast"`
+}
+
+// runExporter implements "carbonctl exporter": a long-lived process that
+// polls the API on an interval and writes the current + forecasted carbon
+// intensity for a set of zones to a Kubernetes ConfigMap manifest on disk,
+// the pattern used by carbon-aware scheduling controllers. With
+// --metrics-addr set, it also serves Prometheus metrics for every provider
+// call, for a CI runner's Prometheus to scrape.
+func runExporter(args []string) error {
+	fs := flag.NewFlagSet("exporter", flag.ContinueOnError)
+	zones := fs.String("zones", "", "comma-separated zone identifiers, e.g. DE,FR")
+	interval := fs.Duration("interval", 5*time.Minute, "polling interval")
+	horizon := fs.Duration("horizon", 4*time.Hour, "forecast horizon embedded in each zone's reading")
+	outputFile := fs.String("output-file", "carbon-intensity-configmap.yaml", "path to write the ConfigMap manifest to")
+	name := fs.String("configmap-name", "carbon-intensity", "metadata.name of the generated ConfigMap")
+	namespace := fs.String("namespace", "default", "metadata.namespace of the generated ConfigMap")
+	metricsAddr := fs.String("metrics-addr", "", "if set, serve Prometheus metrics for every provider call on this address (e.g. :9090)")
+	token := tokenFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	zoneList := splitZones(*zones)
+	if len(zoneList) == 0 {
+		return fmt.Errorf("--zones is required")
+	}
+
+	var instr carbonintensity.Instrumentation
+	if *metricsAddr != "" {
+		reg := prometheus.NewRegistry()
+		prom := carbonintensity.NewPrometheusInstrumentation(reg)
+		instr = prom
+
+		server := &http.Server{Addr: *metricsAddr, Handler: prom.Handler()}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				fmt.Fprintf(os.Stderr, "carbonctl: exporter: metrics server: %v\n", err)
+			}
+		}()
+	}
+
+	provider, err := newProvider(*token, instr)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	e := &exporter{
+		provider:   provider,
+		zones:      zoneList,
+		horizon:    *horizon,
+		outputFile: *outputFile,
+		name:       *name,
+		namespace:  *namespace,
+	}
+
+	e.pollOnce(ctx)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			e.pollOnce(ctx)
+		}
+	}
+}
+
+type exporter struct {
+	provider   carbonintensity.Provider
+	zones      []string
+	horizon    time.Duration
+	outputFile string
+	name       string
+	namespace  string
+}
+
+func (e *exporter) pollOnce(ctx context.Context) {
+	data := make(map[string]string, len(e.zones))
+	for _, zone := range e.zones {
+		reading, err := e.readZone(ctx, zone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "carbonctl: exporter: %s: %v\n", zone, err)
+			continue
+		}
+		data[zone] = reading
+	}
+
+	cm := configMap{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: configMapMetadata{
+			Name:      e.name,
+			Namespace: e.namespace,
+			Annotations: map[string]string{
+				"carbonctl.greensoftware.foundation/last-updated": time.Now().UTC().Format(time.RFC3339),
+			},
+		},
+		Data: data,
+	}
+
+	if err := writeConfigMap(e.outputFile, cm); err != nil {
+		fmt.Fprintf(os.Stderr, "carbonctl: exporter: writing %s: %v\n", e.outputFile, err)
+	}
+}
+
+func (e *exporter) readZone(ctx context.Context, zone string) (string, error) {
+	loc := carbonintensity.Location{Zone: zone}
+
+	live, err := e.provider.LiveIntensity(ctx, loc)
+	if err != nil {
+		return "", fmt.Errorf("live intensity: %w", err)
+	}
+
+	forecast, err := e.provider.Forecast(ctx, loc, e.horizon)
+	if err != nil {
+		return "", fmt.Errorf("forecast: %w", err)
+	}
+
+	reading := zoneReading{
+		CarbonIntensity: live.CarbonIntensity,
+		UpdatedAt:       live.UpdatedAt,
+		Forecast:        forecast,
+	}
+	raw, err := json.Marshal(reading)
+	if err != nil {
+		return "", fmt.Errorf("marshaling reading: %w", err)
+	}
+	return string(raw), nil
+}
+
+// writeConfigMap writes cm to path via a temp file + rename, so a reader
+// (e.g. an admission webhook watching the file) never observes a partial
+// write.
+func writeConfigMap(path string, cm configMap) error {
+	raw, err := yaml.Marshal(cm)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+)
+
+// runIntensity implements "carbonctl intensity <subcommand>".
+func runIntensity(args []string) error {
+	if len(args) == 0 || args[0] != "live" {
+		return fmt.Errorf("usage: carbonctl intensity live --zone ZONE")
+	}
+
+	fs := flag.NewFlagSet("intensity live", flag.ContinueOnError)
+	zone := fs.String("zone", "", "zone identifier, e.g. DE")
+	lat := fs.String("lat", "", "latitude (alternative to --zone)")
+	lon := fs.String("lon", "", "longitude (alternative to --zone)")
+	output := fs.String("output", "table", "output format: json, yaml, table or csv")
+	failAbove := fs.Float64("fail-above", 0, "exit 1 if carbon intensity exceeds this many gCO2eq/kWh (0 disables)")
+	token := tokenFlag(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	provider, err := newProvider(*token, nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	iv, err := provider.LiveIntensity(ctx, carbonintensity.Location{Zone: *zone, Lat: *lat, Lon: *lon})
+	if err != nil {
+		return fmt.Errorf("fetching live carbon intensity: %w", err)
+	}
+
+	table := Table{
+		Columns: []string{"zone", "carbonIntensity", "datetime", "updatedAt"},
+		Rows: [][]string{{
+			iv.Zone,
+			fmt.Sprintf("%g", iv.CarbonIntensity),
+			iv.Datetime.Format(time.RFC3339),
+			iv.UpdatedAt.Format(time.RFC3339),
+		}},
+	}
+	if err := table.Write(os.Stdout, *output); err != nil {
+		return err
+	}
+
+	if *failAbove > 0 && iv.CarbonIntensity > *failAbove {
+		fmt.Fprintf(os.Stderr, "carbonctl: carbon intensity %g exceeds --fail-above %g\n", iv.CarbonIntensity, *failAbove)
+		return exitCode(1)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Command carbonctl is a command-line front end for the carbonintensity
+// package, for driving CI pipelines without embedding the SDK: gate a job on
+// the current carbon intensity, look up a low-carbon execution window, or
+// run a long-lived exporter that keeps a Kubernetes ConfigMap up to date for
+// admission webhooks and Argo Workflows to read.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "intensity":
+		err = runIntensity(args[1:])
+	case "breakdown":
+		err = runBreakdown(args[1:])
+	case "best-window":
+		err = runBestWindow(args[1:])
+	case "exporter":
+		err = runExporter(args[1:])
+	case "-h", "--help", "help":
+		usage()
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "carbonctl: unknown command %q\n", args[0])
+		usage()
+		return 2
+	}
+
+	if err != nil {
+		if code, ok := err.(exitCode); ok {
+			return int(code)
+		}
+		fmt.Fprintf(os.Stderr, "carbonctl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// exitCode lets a subcommand request a specific process exit code (e.g. for
+// --fail-above threshold breaches) without that being treated as an error to
+// print.
+type exitCode int
+
+func (c exitCode) Error() string { return fmt.Sprintf("exit code %d", int(c)) }
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `Usage: carbonctl <command> [flags]
+
+Commands:
+  intensity live --zone ZONE [--output json|yaml|table|csv] [--fail-above N]
+  breakdown past-range --zone ZONE --start TIME --end TIME [--output ...]
+  best-window --zones ZONE,ZONE,... --duration 2h --deadline TIME [--output ...] [--fail-above N]
+  exporter --zones ZONE,ZONE,... [--interval 5m] [--output-file PATH] [--metrics-addr :9090]
+
+The ElectricityMap API token is read from --token or the
+ELECTRICITYMAP_API_TOKEN environment variable.`)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Table is the shared shape every subcommand renders its results into, so
+// --output json|yaml|table|csv behaves identically regardless of which
+// subcommand produced the data.
+type Table struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// Write renders t to w in the given format ("json", "yaml", "table" or
+// "csv"); "table" is used when format is empty.
+func (t Table) Write(w io.Writer, format string) error {
+	switch format {
+	case "", "table":
+		return t.writeTable(w)
+	case "json":
+		return t.writeJSON(w)
+	case "yaml":
+		return t.writeYAML(w)
+	case "csv":
+		return t.writeCSV(w)
+	default:
+		return fmt.Errorf("unknown --output format %q (want json, yaml, table or csv)", format)
+	}
+}
+
+func (t Table) records() []map[string]string {
+	records := make([]map[string]string, len(t.Rows))
+	for i, row := range t.Rows {
+		record := make(map[string]string, len(t.Columns))
+		for j, col := range t.Columns {
+			record[col] = row[j]
+		}
+		records[i] = record
+	}
+	return records
+}
+
+func (t Table) writeJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t.records())
+}
+
+func (t Table) writeYAML(w io.Writer) error {
+	return yaml.NewEncoder(w).Encode(t.records())
+}
+
+func (t Table) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Columns); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (t Table) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, tabJoin(t.Columns))
+	for _, row := range t.Rows {
+		fmt.Fprintln(tw, tabJoin(row))
+	}
+	return tw.Flush()
+}
+
+func tabJoin(fields []string) string {
+	out := ""
+	for i, f := range fields {
+		if i > 0 {
+			out += "\t"
+		}
+		out += f
+	}
+	return out
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/electricitymap"
+)
+
+// tokenFlag registers the --token flag shared by every subcommand and
+// resolves it against ELECTRICITYMAP_API_TOKEN when unset.
+func tokenFlag(fs *flag.FlagSet) *string {
+	return fs.String("token", os.Getenv("ELECTRICITYMAP_API_TOKEN"), "ElectricityMap API token (default: $ELECTRICITYMAP_API_TOKEN)")
+}
+
+func requireToken(token string) (string, error) {
+	if token == "" {
+		return "", fmt.Errorf("no API token: pass --token or set ELECTRICITYMAP_API_TOKEN")
+	}
+	return token, nil
+}
+
+// newProvider builds an ElectricityMap-backed carbonintensity.Provider, for
+// subcommands that only need the vendor-neutral interface. instr is wired
+// in via electricitymap.WithInstrumentation when non-nil, so a subcommand
+// that exposes a metrics endpoint (e.g. "exporter --metrics-addr") can
+// observe every call the provider makes.
+func newProvider(token string, instr carbonintensity.Instrumentation) (carbonintensity.Provider, error) {
+	token, err := requireToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []electricitymap.Option
+	if instr != nil {
+		opts = append(opts, electricitymap.WithInstrumentation(instr))
+	}
+	return electricitymap.NewProvider(token, opts...), nil
+}
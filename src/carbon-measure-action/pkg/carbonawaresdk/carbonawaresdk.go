@@ -0,0 +1,166 @@
+// Package carbonawaresdk is a thin client for the Green Software
+// Foundation's Carbon Aware SDK REST API
+// (https://github.com/Green-Software-Foundation/carbon-aware-sdk), adapted
+// to the carbonintensity.Provider interface.
+package carbonawaresdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+)
+
+// Client talks to a running Carbon Aware SDK WebApi instance.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// New builds a Client pointed at a Carbon Aware SDK WebApi deployment, e.g.
+// "http://localhost:5073".
+func New(baseURL string) *Client {
+	return &Client{baseURL: baseURL, httpClient: &http.Client{}}
+}
+
+// Name identifies this Provider for logging and metric labels.
+func (c *Client) Name() string { return "carbon-aware-sdk" }
+
+type emissionsForLocation struct {
+	Location string  `json:"location"`
+	Time     string  `json:"time"`
+	Rating   float64 `json:"rating"`
+}
+
+// LiveIntensity calls GET /emissions/bylocations/best, which returns the
+// lowest-emissions reading among the requested locations at the given time.
+func (c *Client) LiveIntensity(ctx context.Context, loc carbonintensity.Location) (carbonintensity.Intensity, error) {
+	var data emissionsForLocation
+	q := url.Values{}
+	q.Set("location", loc.Zone)
+	q.Set("time", time.Now().UTC().Format(time.RFC3339))
+
+	if err := c.get(ctx, "/emissions/bylocations/best", q, &data); err != nil {
+		return carbonintensity.Intensity{}, err
+	}
+
+	dt, _ := time.Parse(time.RFC3339, data.Time)
+	return carbonintensity.Intensity{
+		Zone:            data.Location,
+		CarbonIntensity: data.Rating,
+		Datetime:        dt,
+		UpdatedAt:       dt,
+	}, nil
+}
+
+type forecastData struct {
+	Location          string  `json:"location"`
+	GeneratedAt       string  `json:"generatedAt"`
+	OptimalDataPoints []point `json:"optimalDataPoints"`
+	ForecastData      []point `json:"forecastData"`
+}
+
+type point struct {
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// Forecast calls POST /emissions/forecasts/batch for a single location and
+// returns its forecast data points within horizon.
+func (c *Client) Forecast(ctx context.Context, loc carbonintensity.Location, horizon time.Duration) ([]carbonintensity.ForecastPoint, error) {
+	now := time.Now().UTC()
+	body := []map[string]string{{
+		"location":    loc.Zone,
+		"dataStartAt": now.Format(time.RFC3339),
+		"dataEndAt":   now.Add(horizon).Format(time.RFC3339),
+		"requestedAt": now.Format(time.RFC3339),
+	}}
+
+	var data []forecastData
+	if err := c.post(ctx, "/emissions/forecasts/batch", body, &data); err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	out := make([]carbonintensity.ForecastPoint, 0, len(data[0].ForecastData))
+	for _, p := range data[0].ForecastData {
+		ts, err := time.Parse(time.RFC3339, p.Timestamp)
+		if err != nil {
+			continue
+		}
+		out = append(out, carbonintensity.ForecastPoint{Datetime: ts, CarbonIntensity: p.Value})
+	}
+	return out, nil
+}
+
+type averageIntensity struct {
+	Location        string  `json:"location"`
+	StartTime       string  `json:"startTime"`
+	EndTime         string  `json:"endTime"`
+	CarbonIntensity float64 `json:"carbonIntensity"`
+}
+
+// PastRange calls GET /emissions/average-carbon-intensity, which returns a
+// single averaged reading for the window rather than a dense time series.
+func (c *Client) PastRange(ctx context.Context, loc carbonintensity.Location, start, end time.Time) ([]carbonintensity.Intensity, error) {
+	var data averageIntensity
+	q := url.Values{}
+	q.Set("location", loc.Zone)
+	q.Set("startTime", start.UTC().Format(time.RFC3339))
+	q.Set("endTime", end.UTC().Format(time.RFC3339))
+
+	if err := c.get(ctx, "/emissions/average-carbon-intensity", q, &data); err != nil {
+		return nil, err
+	}
+
+	endTime, _ := time.Parse(time.RFC3339, data.EndTime)
+	return []carbonintensity.Intensity{{
+		Zone:            data.Location,
+		CarbonIntensity: data.CarbonIntensity,
+		Datetime:        endTime,
+		UpdatedAt:       endTime,
+	}}, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := fmt.Sprintf("%v%v?%v", c.baseURL, path, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(req, out)
+}
+
+func (c *Client) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	u := fmt.Sprintf("%v%v", c.baseURL, path)
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, out)
+}
+
+func (c *Client) do(req *http.Request, out interface{}) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("carbonawaresdk: %v", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
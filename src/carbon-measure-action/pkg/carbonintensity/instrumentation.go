@@ -0,0 +1,30 @@
+package carbonintensity
+
+import "time"
+
+// Instrumentation is implemented by metrics/tracing backends that want to
+// observe every Provider call: per-endpoint request counts and latency, and
+// the carbon intensity/power mix readings themselves, so dashboards and
+// alerting rules ("fail the build if the next 2h forecast exceeds X
+// gCO2eq/kWh") can be built directly off live pipeline traffic.
+type Instrumentation interface {
+	// ObserveRequest records one upstream HTTP call.
+	ObserveRequest(provider, endpoint string, status int, duration time.Duration)
+	// ObserveCarbonIntensity records a single carbon intensity reading for
+	// zone, such as a live or single past reading.
+	ObserveCarbonIntensity(zone string, gCO2PerKWh float64)
+	// ObservePowerBreakdown records the renewable/fossil-free share of
+	// zone's power mix, as returned by a power breakdown endpoint.
+	ObservePowerBreakdown(zone string, renewablePercentage, fossilFreePercentage float64)
+}
+
+// NopInstrumentation discards every observation. It's the default so
+// Provider implementations stay zero-config unless instrumentation is
+// explicitly wired in.
+type NopInstrumentation struct{}
+
+func (NopInstrumentation) ObserveRequest(provider, endpoint string, status int, duration time.Duration) {
+}
+func (NopInstrumentation) ObserveCarbonIntensity(zone string, gCO2PerKWh float64) {}
+func (NopInstrumentation) ObservePowerBreakdown(zone string, renewablePercentage, fossilFreePercentage float64) {
+}
@@ -0,0 +1,212 @@
+package carbonintensity
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Strategy controls how Multi reconciles results from several providers that
+// were queried for the same Location.
+type Strategy int
+
+const (
+	// FirstSuccess returns the first provider response that didn't error,
+	// in whatever order the providers happen to answer.
+	FirstSuccess Strategy = iota
+	// Average returns the mean of every successful response. Only
+	// LiveIntensity supports it; Forecast/PastRange fall back to
+	// FirstSuccess since averaging series with different horizons or
+	// resolutions isn't well defined.
+	Average
+	// Freshest returns the response with the most recent UpdatedAt
+	// (LiveIntensity, and the final sample of PastRange) or first-sample
+	// Datetime (Forecast).
+	Freshest
+)
+
+// ErrNoProviders is returned when a Multi has no providers configured.
+var ErrNoProviders = errors.New("carbonintensity: no providers configured")
+
+// Multi fans a request out to several Provider backends in parallel and
+// reconciles their answers according to Strategy, so a CI pipeline isn't
+// wedged when a single backend is down or rate-limited.
+type Multi struct {
+	providers []Provider
+	strategy  Strategy
+}
+
+// NewMulti builds a Multi that queries providers in parallel and reconciles
+// their responses using strategy.
+func NewMulti(strategy Strategy, providers ...Provider) *Multi {
+	return &Multi{providers: providers, strategy: strategy}
+}
+
+// Name identifies this Provider for logging and metric labels.
+func (m *Multi) Name() string { return "multi" }
+
+type liveResult struct {
+	provider Provider
+	value    Intensity
+	err      error
+}
+
+// LiveIntensity queries every configured provider in parallel and reconciles
+// their answers per m.strategy.
+func (m *Multi) LiveIntensity(ctx context.Context, loc Location) (Intensity, error) {
+	if len(m.providers) == 0 {
+		return Intensity{}, ErrNoProviders
+	}
+
+	results := make(chan liveResult, len(m.providers))
+	for _, p := range m.providers {
+		p := p
+		go func() {
+			v, err := p.LiveIntensity(ctx, loc)
+			results <- liveResult{provider: p, value: v, err: err}
+		}()
+	}
+
+	var ok []liveResult
+	var lastErr error
+	for i := 0; i < len(m.providers); i++ {
+		r := <-results
+		if r.err != nil {
+			lastErr = r.err
+			continue
+		}
+		ok = append(ok, r)
+		if m.strategy == FirstSuccess {
+			return r.value, nil
+		}
+	}
+
+	if len(ok) == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("carbonintensity: all providers returned no result")
+		}
+		return Intensity{}, lastErr
+	}
+
+	switch m.strategy {
+	case Freshest:
+		freshest := ok[0]
+		for _, r := range ok[1:] {
+			if r.value.UpdatedAt.After(freshest.value.UpdatedAt) {
+				freshest = r
+			}
+		}
+		return freshest.value, nil
+	default: // Average
+		var sum float64
+		for _, r := range ok {
+			sum += r.value.CarbonIntensity
+		}
+		avg := ok[0].value
+		avg.CarbonIntensity = sum / float64(len(ok))
+		avg.Zone = loc.Zone
+		return avg, nil
+	}
+}
+
+type seriesResult struct {
+	value []ForecastPoint
+	raw   []Intensity
+	err   error
+}
+
+// Forecast queries every configured provider in parallel and returns the
+// first successful response (or, under Freshest, the one generated from the
+// most recent sample). Average is not meaningful for series data and is
+// treated as FirstSuccess.
+func (m *Multi) Forecast(ctx context.Context, loc Location, horizon time.Duration) ([]ForecastPoint, error) {
+	if len(m.providers) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	results := make(chan seriesResult, len(m.providers))
+	for _, p := range m.providers {
+		p := p
+		go func() {
+			v, err := p.Forecast(ctx, loc, horizon)
+			results <- seriesResult{value: v, err: err}
+		}()
+	}
+
+	var ok [][]ForecastPoint
+	var lastErr error
+	for i := 0; i < len(m.providers); i++ {
+		r := <-results
+		if r.err != nil {
+			lastErr = r.err
+			continue
+		}
+		ok = append(ok, r.value)
+		if m.strategy != Freshest {
+			return r.value, nil
+		}
+	}
+
+	if len(ok) == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("carbonintensity: all providers returned no result")
+		}
+		return nil, lastErr
+	}
+
+	freshest := ok[0]
+	for _, v := range ok[1:] {
+		if len(v) > 0 && (len(freshest) == 0 || v[0].Datetime.After(freshest[0].Datetime)) {
+			freshest = v
+		}
+	}
+	return freshest, nil
+}
+
+// PastRange queries every configured provider in parallel and returns the
+// first successful response (or, under Freshest, the one whose final sample
+// has the most recent UpdatedAt). Average is not meaningful for series data and is
+// treated as FirstSuccess.
+func (m *Multi) PastRange(ctx context.Context, loc Location, start, end time.Time) ([]Intensity, error) {
+	if len(m.providers) == 0 {
+		return nil, ErrNoProviders
+	}
+
+	results := make(chan seriesResult, len(m.providers))
+	for _, p := range m.providers {
+		p := p
+		go func() {
+			v, err := p.PastRange(ctx, loc, start, end)
+			results <- seriesResult{raw: v, err: err}
+		}()
+	}
+
+	var ok [][]Intensity
+	var lastErr error
+	for i := 0; i < len(m.providers); i++ {
+		r := <-results
+		if r.err != nil {
+			lastErr = r.err
+			continue
+		}
+		ok = append(ok, r.raw)
+		if m.strategy != Freshest {
+			return r.raw, nil
+		}
+	}
+
+	if len(ok) == 0 {
+		if lastErr == nil {
+			lastErr = errors.New("carbonintensity: all providers returned no result")
+		}
+		return nil, lastErr
+	}
+
+	freshest := ok[0]
+	for _, v := range ok[1:] {
+		if len(v) > 0 && (len(freshest) == 0 || v[len(v)-1].UpdatedAt.After(freshest[len(freshest)-1].UpdatedAt)) {
+			freshest = v
+		}
+	}
+	return freshest, nil
+}
@@ -0,0 +1,137 @@
+package carbonintensity
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// PrometheusInstrumentation is an Instrumentation backed by Prometheus
+// client_golang metrics, ready to mount behind an http.Handler so a CI
+// runner's Prometheus can scrape it and drive Grafana dashboards or
+// alertmanager rules.
+type PrometheusInstrumentation struct {
+	gatherer   prometheus.Gatherer
+	requests   *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	intensity  *prometheus.GaugeVec
+	renewable  *prometheus.GaugeVec
+	fossilFree *prometheus.GaugeVec
+}
+
+// NewPrometheusInstrumentation registers its metrics with reg (pass
+// prometheus.DefaultRegisterer to use the global registry, or
+// prometheus.NewRegistry() for test isolation or multi-tenant scraping).
+// reg must also implement prometheus.Gatherer - true of both of the above -
+// since Handler scrapes it directly rather than the global registry.
+func NewPrometheusInstrumentation(reg prometheus.Registerer) *PrometheusInstrumentation {
+	gatherer, ok := reg.(prometheus.Gatherer)
+	if !ok {
+		panic("carbonintensity: registerer does not implement prometheus.Gatherer")
+	}
+
+	p := &PrometheusInstrumentation{
+		gatherer: gatherer,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "carbon_ci_provider_requests_total",
+			Help: "Number of carbon intensity provider API calls, labeled by provider, endpoint and HTTP status.",
+		}, []string{"provider", "endpoint", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "carbon_ci_provider_request_duration_seconds",
+			Help:    "Latency of carbon intensity provider API calls, labeled by provider and endpoint.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"provider", "endpoint"}),
+		intensity: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "carbon_intensity_gco2_per_kwh",
+			Help: "Most recently observed carbon intensity, labeled by zone.",
+		}, []string{"zone"}),
+		renewable: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "renewable_percentage",
+			Help: "Most recently observed renewable share of a zone's power mix.",
+		}, []string{"zone"}),
+		fossilFree: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "fossil_free_percentage",
+			Help: "Most recently observed fossil-free share of a zone's power mix.",
+		}, []string{"zone"}),
+	}
+
+	reg.MustRegister(p.requests, p.latency, p.intensity, p.renewable, p.fossilFree)
+	return p
+}
+
+// ObserveRequest implements Instrumentation.
+func (p *PrometheusInstrumentation) ObserveRequest(provider, endpoint string, status int, duration time.Duration) {
+	p.requests.WithLabelValues(provider, endpoint, statusLabel(status)).Inc()
+	p.latency.WithLabelValues(provider, endpoint).Observe(duration.Seconds())
+}
+
+// ObserveCarbonIntensity implements Instrumentation.
+func (p *PrometheusInstrumentation) ObserveCarbonIntensity(zone string, gCO2PerKWh float64) {
+	p.intensity.WithLabelValues(zone).Set(gCO2PerKWh)
+}
+
+// ObservePowerBreakdown implements Instrumentation.
+func (p *PrometheusInstrumentation) ObservePowerBreakdown(zone string, renewablePercentage, fossilFreePercentage float64) {
+	p.renewable.WithLabelValues(zone).Set(renewablePercentage)
+	p.fossilFree.WithLabelValues(zone).Set(fossilFreePercentage)
+}
+
+// Handler returns a ready-to-mount http.Handler exposing these metrics in
+// the Prometheus exposition format, scraping the registry passed to
+// NewPrometheusInstrumentation rather than the global DefaultGatherer.
+func (p *PrometheusInstrumentation) Handler() http.Handler {
+	return promhttp.HandlerFor(p.gatherer, promhttp.HandlerOpts{})
+}
+
+func statusLabel(status int) string {
+	if status == 0 {
+		return "error"
+	}
+	return strconv.Itoa(status)
+}
+
+// Scraper periodically polls a Provider for a fixed set of zones and feeds
+// the readings into its Instrumentation, so metrics stay fresh even for
+// zones no pipeline step happens to query directly.
+type Scraper struct {
+	provider Provider
+	zones    []string
+	interval time.Duration
+	instr    Instrumentation
+}
+
+// NewScraper builds a Scraper that polls provider for zones every
+// interval, recording each reading via instr.
+func NewScraper(provider Provider, zones []string, interval time.Duration, instr Instrumentation) *Scraper {
+	return &Scraper{provider: provider, zones: zones, interval: interval, instr: instr}
+}
+
+// Run polls until ctx is cancelled. Call it in its own goroutine.
+func (s *Scraper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.scrapeOnce(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.scrapeOnce(ctx)
+		}
+	}
+}
+
+func (s *Scraper) scrapeOnce(ctx context.Context) {
+	for _, zone := range s.zones {
+		iv, err := s.provider.LiveIntensity(ctx, Location{Zone: zone})
+		if err != nil {
+			continue
+		}
+		s.instr.ObserveCarbonIntensity(zone, iv.CarbonIntensity)
+	}
+}
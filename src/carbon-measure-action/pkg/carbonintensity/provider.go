@@ -0,0 +1,52 @@
+// Package carbonintensity defines a vendor-neutral view of grid carbon
+// intensity so that CI pipeline tooling can target ElectricityMap, the Green
+// Software Foundation's Carbon Aware SDK, RTE eCO2mix, WattTime or any
+// combination of them through a single interface.
+package carbonintensity
+
+import (
+	"context"
+	"time"
+)
+
+// Location identifies where a reading should be taken, either by zone
+// identifier (as used by ElectricityMap/Carbon Aware SDK) or by raw
+// coordinates. Providers that only support one form should ignore the other.
+type Location struct {
+	Zone string
+	Lat  string
+	Lon  string
+}
+
+// Intensity is a single point-in-time carbon intensity reading for a zone.
+type Intensity struct {
+	Zone            string
+	CarbonIntensity float64 // gCO2eq/kWh
+	Datetime        time.Time
+	UpdatedAt       time.Time
+}
+
+// ForecastPoint is one predicted sample within a Forecast.
+type ForecastPoint struct {
+	Datetime        time.Time
+	CarbonIntensity float64 // gCO2eq/kWh
+}
+
+// Provider is implemented by every carbon intensity backend so pipeline code
+// can swap vendors - or fan out to several of them via Multi - without
+// rewriting call sites.
+type Provider interface {
+	// Name identifies the backend, e.g. for logging and metric labels.
+	Name() string
+
+	// LiveIntensity returns the most recently known carbon intensity for loc.
+	LiveIntensity(ctx context.Context, loc Location) (Intensity, error)
+
+	// Forecast returns predicted carbon intensity for loc over the given
+	// horizon, starting from now.
+	Forecast(ctx context.Context, loc Location, horizon time.Duration) ([]ForecastPoint, error)
+
+	// PastRange returns historical intensity samples for loc between start
+	// and end (end exclusive, mirroring the ElectricityMap past-range API).
+	PastRange(ctx context.Context, loc Location, start, end time.Time) ([]Intensity, error)
+}
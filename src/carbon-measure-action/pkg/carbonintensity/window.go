@@ -0,0 +1,154 @@
+package carbonintensity
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Window is a contiguous span of time together with the average carbon
+// intensity forecast over that span, as returned by BestExecutionWindow.
+type Window struct {
+	Zone             string
+	Start            time.Time
+	End              time.Time
+	AverageIntensity float64 // gCO2eq/kWh
+}
+
+// ErrNoWindowFits is returned by BestExecutionWindow when none of the given
+// zones have a forecast window of the requested duration ending before
+// deadline.
+var ErrNoWindowFits = errors.New("carbonintensity: no forecast window of the requested duration fits before the deadline")
+
+// ErrNoIntensityAvailable is returned by BestLocation when none of the given
+// zones produced an intensity reading.
+var ErrNoIntensityAvailable = errors.New("carbonintensity: no intensity reading available for any zone")
+
+// BestExecutionWindow answers the question CI pipelines actually have -
+// "when in the next few hours should I run this job?" - by forecasting each
+// of zones and returning the contiguous window of the given duration with
+// the lowest average carbon intensity that still finishes before deadline.
+func BestExecutionWindow(ctx context.Context, p Provider, zones []string, duration time.Duration, deadline time.Time) (Window, error) {
+	horizon := time.Until(deadline)
+	if horizon <= 0 {
+		return Window{}, ErrNoWindowFits
+	}
+
+	var best Window
+	found := false
+	for _, zone := range zones {
+		points, err := p.Forecast(ctx, Location{Zone: zone}, horizon)
+		if err != nil {
+			continue
+		}
+
+		w, ok := bestWindowIn(zone, points, duration, deadline)
+		if !ok {
+			continue
+		}
+		if !found || w.AverageIntensity < best.AverageIntensity {
+			best, found = w, true
+		}
+	}
+
+	if !found {
+		return Window{}, ErrNoWindowFits
+	}
+	return best, nil
+}
+
+// bestWindowIn returns the window of the given duration, ending no later
+// than deadline, with the lowest average carbon intensity among points.
+func bestWindowIn(zone string, points []ForecastPoint, duration time.Duration, deadline time.Time) (Window, bool) {
+	sorted := append([]ForecastPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Datetime.Before(sorted[j].Datetime) })
+
+	var best Window
+	found := false
+	for i, p := range sorted {
+		end := p.Datetime.Add(duration)
+		if end.After(deadline) {
+			continue
+		}
+
+		var sum float64
+		var n int
+		for _, q := range sorted[i:] {
+			if !q.Datetime.Before(end) {
+				break
+			}
+			sum += q.CarbonIntensity
+			n++
+		}
+		if n == 0 {
+			continue
+		}
+
+		avg := sum / float64(n)
+		if !found || avg < best.AverageIntensity {
+			best = Window{Zone: zone, Start: p.Datetime, End: end, AverageIntensity: avg}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// BestLocation returns the zone with the lowest carbon intensity among
+// zones at the given instant, mirroring the Carbon Aware SDK's
+// bylocations/best semantics. Instants close to now use each provider's
+// live reading; instants in the future fall back to their forecast.
+func BestLocation(ctx context.Context, p Provider, zones []string, at time.Time) (Intensity, error) {
+	const liveTolerance = 5 * time.Minute
+
+	var best Intensity
+	found := false
+	for _, zone := range zones {
+		iv, err := intensityAt(ctx, p, zone, at, liveTolerance)
+		if err != nil {
+			continue
+		}
+		if !found || iv.CarbonIntensity < best.CarbonIntensity {
+			best, found = iv, true
+		}
+	}
+
+	if !found {
+		return Intensity{}, ErrNoIntensityAvailable
+	}
+	return best, nil
+}
+
+func intensityAt(ctx context.Context, p Provider, zone string, at time.Time, liveTolerance time.Duration) (Intensity, error) {
+	horizon := time.Until(at)
+	if horizon <= liveTolerance {
+		return p.LiveIntensity(ctx, Location{Zone: zone})
+	}
+
+	points, err := p.Forecast(ctx, Location{Zone: zone}, horizon)
+	if err != nil {
+		return Intensity{}, err
+	}
+
+	closest, ok := closestPoint(points, at)
+	if !ok {
+		return Intensity{}, errors.New("carbonintensity: no forecast point near the requested time")
+	}
+	return Intensity{Zone: zone, CarbonIntensity: closest.CarbonIntensity, Datetime: closest.Datetime, UpdatedAt: closest.Datetime}, nil
+}
+
+func closestPoint(points []ForecastPoint, at time.Time) (ForecastPoint, bool) {
+	var best ForecastPoint
+	var bestDiff time.Duration
+	found := false
+	for _, p := range points {
+		diff := p.Datetime.Sub(at)
+		if diff < 0 {
+			diff = -diff
+		}
+		if !found || diff < bestDiff {
+			best, bestDiff, found = p, diff, true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,213 @@
+package carbonintensity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeProvider is a Provider backed by fixed per-zone responses, for
+// exercising BestExecutionWindow/BestLocation without a real backend.
+type fakeProvider struct {
+	forecasts   map[string][]ForecastPoint
+	live        map[string]Intensity
+	forecastErr map[string]error
+	liveErr     map[string]error
+}
+
+func (f *fakeProvider) Name() string { return "fake" }
+
+func (f *fakeProvider) LiveIntensity(ctx context.Context, loc Location) (Intensity, error) {
+	if err := f.liveErr[loc.Zone]; err != nil {
+		return Intensity{}, err
+	}
+	iv, ok := f.live[loc.Zone]
+	if !ok {
+		return Intensity{}, errors.New("fakeProvider: no live reading for zone")
+	}
+	return iv, nil
+}
+
+func (f *fakeProvider) Forecast(ctx context.Context, loc Location, horizon time.Duration) ([]ForecastPoint, error) {
+	if err := f.forecastErr[loc.Zone]; err != nil {
+		return nil, err
+	}
+	return f.forecasts[loc.Zone], nil
+}
+
+func (f *fakeProvider) PastRange(ctx context.Context, loc Location, start, end time.Time) ([]Intensity, error) {
+	return nil, errors.New("fakeProvider: PastRange not implemented")
+}
+
+func points(base time.Time, step time.Duration, values ...float64) []ForecastPoint {
+	out := make([]ForecastPoint, len(values))
+	for i, v := range values {
+		out[i] = ForecastPoint{Datetime: base.Add(time.Duration(i) * step), CarbonIntensity: v}
+	}
+	return out
+}
+
+func TestBestWindowInPicksLowestAverage(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// hourly samples: 100, 100, 10, 10, 100 gCO2eq/kWh
+	fc := points(base, time.Hour, 100, 100, 10, 10, 100)
+	deadline := base.Add(5 * time.Hour)
+
+	w, ok := bestWindowIn("DE", fc, 2*time.Hour, deadline)
+	if !ok {
+		t.Fatalf("bestWindowIn: no window found")
+	}
+	if w.AverageIntensity != 10 {
+		t.Errorf("AverageIntensity = %v, want 10", w.AverageIntensity)
+	}
+	if !w.Start.Equal(base.Add(2 * time.Hour)) {
+		t.Errorf("Start = %v, want %v", w.Start, base.Add(2*time.Hour))
+	}
+	if w.Zone != "DE" {
+		t.Errorf("Zone = %q, want \"DE\"", w.Zone)
+	}
+}
+
+func TestBestWindowInRespectsDeadline(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// the lowest-intensity sample is last, but a 2h window starting there
+	// would end after the deadline and must be excluded.
+	fc := points(base, time.Hour, 100, 100, 100, 1)
+	deadline := base.Add(3 * time.Hour)
+
+	w, ok := bestWindowIn("DE", fc, 2*time.Hour, deadline)
+	if !ok {
+		t.Fatalf("bestWindowIn: no window found")
+	}
+	if w.Start.Equal(base.Add(3 * time.Hour)) {
+		t.Fatalf("selected window starting at the last sample, which violates the deadline")
+	}
+}
+
+func TestBestWindowInNoFit(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	fc := points(base, time.Hour, 100, 100)
+
+	if _, ok := bestWindowIn("DE", fc, 24*time.Hour, base.Add(time.Hour)); ok {
+		t.Fatalf("bestWindowIn found a window longer than the available forecast")
+	}
+}
+
+func TestBestExecutionWindowAcrossZones(t *testing.T) {
+	base := time.Now().Truncate(time.Hour)
+	p := &fakeProvider{
+		forecasts: map[string][]ForecastPoint{
+			"DE": points(base, time.Hour, 100, 100, 100),
+			"FR": points(base, time.Hour, 10, 10, 10),
+		},
+	}
+
+	w, err := BestExecutionWindow(context.Background(), p, []string{"DE", "FR"}, time.Hour, base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("BestExecutionWindow: %v", err)
+	}
+	if w.Zone != "FR" {
+		t.Errorf("Zone = %q, want \"FR\"", w.Zone)
+	}
+}
+
+func TestBestExecutionWindowSkipsErroringProviders(t *testing.T) {
+	base := time.Now().Truncate(time.Hour)
+	p := &fakeProvider{
+		forecasts: map[string][]ForecastPoint{
+			"FR": points(base, time.Hour, 10, 10, 10),
+		},
+		forecastErr: map[string]error{"DE": errors.New("boom")},
+	}
+
+	w, err := BestExecutionWindow(context.Background(), p, []string{"DE", "FR"}, time.Hour, base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("BestExecutionWindow: %v", err)
+	}
+	if w.Zone != "FR" {
+		t.Errorf("Zone = %q, want \"FR\"", w.Zone)
+	}
+}
+
+func TestBestExecutionWindowNoneFit(t *testing.T) {
+	p := &fakeProvider{forecasts: map[string][]ForecastPoint{}}
+
+	_, err := BestExecutionWindow(context.Background(), p, []string{"DE"}, time.Hour, time.Now().Add(time.Hour))
+	if !errors.Is(err, ErrNoWindowFits) {
+		t.Errorf("err = %v, want ErrNoWindowFits", err)
+	}
+}
+
+func TestBestExecutionWindowPastDeadline(t *testing.T) {
+	p := &fakeProvider{}
+
+	_, err := BestExecutionWindow(context.Background(), p, []string{"DE"}, time.Hour, time.Now().Add(-time.Hour))
+	if !errors.Is(err, ErrNoWindowFits) {
+		t.Errorf("err = %v, want ErrNoWindowFits", err)
+	}
+}
+
+func TestBestLocationPicksLowestLiveIntensity(t *testing.T) {
+	p := &fakeProvider{
+		live: map[string]Intensity{
+			"DE": {Zone: "DE", CarbonIntensity: 300},
+			"FR": {Zone: "FR", CarbonIntensity: 50},
+		},
+	}
+
+	iv, err := BestLocation(context.Background(), p, []string{"DE", "FR"}, time.Now())
+	if err != nil {
+		t.Fatalf("BestLocation: %v", err)
+	}
+	if iv.Zone != "FR" {
+		t.Errorf("Zone = %q, want \"FR\"", iv.Zone)
+	}
+}
+
+func TestBestLocationNoReadingsAvailable(t *testing.T) {
+	p := &fakeProvider{
+		liveErr: map[string]error{"DE": errors.New("boom")},
+	}
+
+	_, err := BestLocation(context.Background(), p, []string{"DE"}, time.Now())
+	if !errors.Is(err, ErrNoIntensityAvailable) {
+		t.Errorf("err = %v, want ErrNoIntensityAvailable", err)
+	}
+}
+
+func TestBestLocationFutureInstantUsesForecast(t *testing.T) {
+	base := time.Now().Add(time.Hour)
+	p := &fakeProvider{
+		forecasts: map[string][]ForecastPoint{
+			"DE": {{Datetime: base, CarbonIntensity: 42}},
+		},
+	}
+
+	iv, err := BestLocation(context.Background(), p, []string{"DE"}, base)
+	if err != nil {
+		t.Fatalf("BestLocation: %v", err)
+	}
+	if iv.CarbonIntensity != 42 {
+		t.Errorf("CarbonIntensity = %v, want 42", iv.CarbonIntensity)
+	}
+}
+
+func TestClosestPointPicksNearestSample(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	pts := points(base, time.Hour, 1, 2, 3)
+
+	closest, ok := closestPoint(pts, base.Add(100*time.Minute))
+	if !ok {
+		t.Fatalf("closestPoint: no point found")
+	}
+	if closest.CarbonIntensity != 3 {
+		t.Errorf("CarbonIntensity = %v, want 3 (the 2h sample, 20m away vs. 40m for the 1h sample)", closest.CarbonIntensity)
+	}
+}
+
+func TestClosestPointEmpty(t *testing.T) {
+	if _, ok := closestPoint(nil, time.Now()); ok {
+		t.Fatalf("closestPoint on an empty slice reported a match")
+	}
+}
@@ -0,0 +1,87 @@
+package electricitymap
+
+import (
+	"context"
+	"time"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+)
+
+// Adapter exposes an ElectricityMap client as a carbonintensity.Provider.
+type Adapter struct {
+	client electricityMap
+}
+
+// NewProvider builds an ElectricityMap-backed carbonintensity.Provider
+// authenticated with the given API token. opts are passed through to New,
+// e.g. WithInstrumentation to observe every call made through the Provider.
+func NewProvider(zoneKey string, opts ...Option) *Adapter {
+	return &Adapter{client: New(zoneKey, opts...)}
+}
+
+// Name identifies this Provider for logging and metric labels.
+func (a *Adapter) Name() string { return "electricitymap" }
+
+// LiveIntensity implements carbonintensity.Provider.
+func (a *Adapter) LiveIntensity(ctx context.Context, loc carbonintensity.Location) (carbonintensity.Intensity, error) {
+	data, err := a.client.LiveCarbonIntensity(ctx, TypAPIParams{Zone: loc.Zone, Lat: loc.Lat, Lon: loc.Lon})
+	if err != nil {
+		return carbonintensity.Intensity{}, err
+	}
+	return toIntensity(data), nil
+}
+
+// Forecast implements carbonintensity.Provider. horizon is honored
+// client-side since ElectricityMap's forecast endpoint always returns its
+// full ~24h forecast window regardless of what's requested.
+func (a *Adapter) Forecast(ctx context.Context, loc carbonintensity.Location, horizon time.Duration) ([]carbonintensity.ForecastPoint, error) {
+	data, err := a.client.ForecastCarbonIntensity(ctx, TypAPIParams{Zone: loc.Zone, Lat: loc.Lat, Lon: loc.Lon})
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(horizon)
+	points := make([]carbonintensity.ForecastPoint, 0, len(data.Forecast))
+	for _, f := range data.Forecast {
+		dt, err := time.Parse(time.RFC3339, f.Datetime)
+		if err != nil {
+			continue
+		}
+		if horizon > 0 && dt.After(cutoff) {
+			continue
+		}
+		points = append(points, carbonintensity.ForecastPoint{Datetime: dt, CarbonIntensity: float64(f.CarbonIntensity)})
+	}
+	return points, nil
+}
+
+// PastRange implements carbonintensity.Provider.
+func (a *Adapter) PastRange(ctx context.Context, loc carbonintensity.Location, start, end time.Time) ([]carbonintensity.Intensity, error) {
+	data, err := a.client.PastCarbonIntensityRange(ctx, TypAPIParams{
+		Zone:  loc.Zone,
+		Lat:   loc.Lat,
+		Lon:   loc.Lon,
+		Start: start.Format(time.RFC3339),
+		End:   end.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]carbonintensity.Intensity, 0, len(data.History))
+	for _, ci := range data.History {
+		out = append(out, toIntensity(ci))
+	}
+	return out, nil
+}
+
+func toIntensity(ci typCI) carbonintensity.Intensity {
+	dt, _ := time.Parse(time.RFC3339, ci.Datetime)
+	updated, _ := time.Parse(time.RFC3339, ci.UpdatedAt)
+	return carbonintensity.Intensity{
+		Zone:            ci.Zone,
+		CarbonIntensity: float64(ci.CarbonIntensity),
+		Datetime:        dt,
+		UpdatedAt:       updated,
+	}
+}
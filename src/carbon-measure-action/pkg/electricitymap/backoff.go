@@ -0,0 +1,53 @@
+package electricitymap
+
+import (
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// RetryConfig controls the exponential backoff with jitter used by httpGet
+// when the API responds with 429 or 503.
+type RetryConfig struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryConfig is used by New unless overridden with WithRetry.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+// nextDelay returns how long to wait before retry attempt n (0-indexed). If
+// the upstream sent a Retry-After duration it's honored as-is; otherwise
+// this falls back to exponential backoff with full jitter.
+func (c RetryConfig) nextDelay(n int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+
+	delay := c.BaseDelay * time.Duration(1<<uint(n))
+	if delay > c.MaxDelay || delay <= 0 {
+		delay = c.MaxDelay
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// parseRetryAfter parses the numeric-seconds form of a Retry-After header.
+// The HTTP-date form is rare for rate-limit responses and isn't supported;
+// an unparsable or empty header yields 0, signalling "fall back to backoff".
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,63 @@
+package electricitymap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryConfigNextDelayHonorsRetryAfter(t *testing.T) {
+	cfg := DefaultRetryConfig()
+
+	got := cfg.nextDelay(0, 7*time.Second)
+	if got != 7*time.Second {
+		t.Errorf("nextDelay with retryAfter = %v, want 7s", got)
+	}
+}
+
+func TestRetryConfigNextDelayBackoffBounds(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for n := 0; n < cfg.MaxRetries; n++ {
+		for i := 0; i < 20; i++ { // jitter is random; sample repeatedly
+			got := cfg.nextDelay(n, 0)
+			if got < 0 || got > cfg.MaxDelay {
+				t.Fatalf("nextDelay(%d, 0) = %v, want in [0, %v]", n, got, cfg.MaxDelay)
+			}
+		}
+	}
+}
+
+func TestRetryConfigNextDelayCapsAtMaxDelay(t *testing.T) {
+	cfg := RetryConfig{MaxRetries: 10, BaseDelay: time.Second, MaxDelay: 2 * time.Second}
+
+	// Attempt 5 would be 1s * 2^5 = 32s uncapped; nextDelay must fall back
+	// to jittering within [0, MaxDelay] instead of growing unbounded.
+	for i := 0; i < 20; i++ {
+		got := cfg.nextDelay(5, 0)
+		if got > cfg.MaxDelay {
+			t.Fatalf("nextDelay(5, 0) = %v, want <= MaxDelay %v", got, cfg.MaxDelay)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "5", 5 * time.Second},
+		{"zero", "0", 0},
+		{"negative", "-1", 0},
+		{"not a number", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.header); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
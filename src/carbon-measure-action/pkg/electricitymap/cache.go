@@ -0,0 +1,165 @@
+package electricitymap
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores raw JSON API responses keyed by endpoint + query parameters,
+// so a CI pipeline re-running for the same zone within an endpoint's
+// resolution window doesn't burn the (rate-limited) API quota. A ttl of 0
+// passed to Set means the entry never expires, which is used for
+// already-settled historical data.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// CacheMetrics holds Prometheus-style hit/miss/coalesced counters for a
+// client's cache. Safe for concurrent use.
+type CacheMetrics struct {
+	mu        sync.Mutex
+	hits      uint64
+	misses    uint64
+	coalesced uint64
+}
+
+func (m *CacheMetrics) recordHit()       { m.mu.Lock(); m.hits++; m.mu.Unlock() }
+func (m *CacheMetrics) recordMiss()      { m.mu.Lock(); m.misses++; m.mu.Unlock() }
+func (m *CacheMetrics) recordCoalesced() { m.mu.Lock(); m.coalesced++; m.mu.Unlock() }
+
+// Hits returns the number of cache lookups that found a live entry.
+func (m *CacheMetrics) Hits() uint64 { m.mu.Lock(); defer m.mu.Unlock(); return m.hits }
+
+// Misses returns the number of cache lookups that required an upstream
+// fetch.
+func (m *CacheMetrics) Misses() uint64 { m.mu.Lock(); defer m.mu.Unlock(); return m.misses }
+
+// Coalesced returns the number of requests that were served by a fetch
+// already in flight for the same key, rather than starting a new one.
+func (m *CacheMetrics) Coalesced() uint64 { m.mu.Lock(); defer m.mu.Unlock(); return m.coalesced }
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero means "never expires"
+}
+
+// LRUCache is the default in-memory Cache implementation: a fixed-capacity
+// least-recently-used cache with per-entry TTLs.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewLRUCache builds an LRUCache holding at most capacity entries.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = 128
+	}
+	return &LRUCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return entry.value, true
+}
+
+// Set implements Cache. ttl <= 0 means the entry never expires.
+func (c *LRUCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry).value = value
+		el.Value.(*lruEntry).expiresAt = expiresAt
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	c.items[key] = el
+
+	if c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest != nil {
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
+
+// pastSettledAfter is how long ElectricityMap keeps revising "past" readings
+// via its estimation fallback. Past windows ending more recently than this
+// are still provisional and must not be cached forever.
+const pastSettledAfter = 24 * time.Hour
+
+// ttlForEndpoint returns the default TTL for a request to endpoint with the
+// given query parameters, matching each endpoint's natural data resolution:
+// ~5 minutes for "latest" readings, 60 minutes for hourly history/forecast
+// series. "past" endpoints get no expiry, but only once the requested
+// window is old enough (more than pastSettledAfter) that ElectricityMap
+// won't revise it anymore; a recently-requested past window falls back to
+// the hourly TTL like any other series data.
+func ttlForEndpoint(endpoint string, query map[string]string) time.Duration {
+	switch {
+	case strings.Contains(endpoint, "/past"):
+		if pastWindowSettled(query) {
+			return 0
+		}
+		return 60 * time.Minute
+	case strings.Contains(endpoint, "/latest"):
+		return 5 * time.Minute
+	default: // /history, /forecast
+		return 60 * time.Minute
+	}
+}
+
+// pastWindowSettled reports whether the "end" (or, for single-point
+// queries, "datetime") parameter of a past/past-range query is far enough
+// in the past that the reading is done being revised. An unparseable or
+// missing value is treated as unsettled, so caching stays conservative.
+func pastWindowSettled(query map[string]string) bool {
+	value := query["end"]
+	if value == "" {
+		value = query["datetime"]
+	}
+	if value == "" {
+		return false
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return false
+	}
+	return time.Since(t) > pastSettledAfter
+}
@@ -0,0 +1,167 @@
+package electricitymap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUCacheGetSet(t *testing.T) {
+	c := NewLRUCache(2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get on empty cache found a value")
+	}
+
+	c.Set("a", []byte("1"), 0)
+	v, ok := c.Get("a")
+	if !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v, want \"1\", true", v, ok)
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+	c.Get("a") // touch a so b becomes the least recently used
+	c.Set("c", []byte("3"), 0)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("b was not evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("a was evicted, want it kept (recently touched)")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Fatalf("c was not inserted")
+	}
+}
+
+func TestLRUCacheTTLExpiry(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("Get(a) found an entry past its TTL")
+	}
+}
+
+func TestLRUCacheZeroTTLNeverExpires(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("Get(a) expired an entry with ttl <= 0")
+	}
+}
+
+func TestLRUCacheSetOverwritesExisting(t *testing.T) {
+	c := NewLRUCache(2)
+
+	c.Set("a", []byte("1"), 0)
+	c.Set("a", []byte("2"), 0)
+
+	v, ok := c.Get("a")
+	if !ok || string(v) != "2" {
+		t.Fatalf("Get(a) = %q, %v, want \"2\", true", v, ok)
+	}
+}
+
+func TestTtlForEndpoint(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour).Format(time.RFC3339)
+	recent := time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		endpoint string
+		query    map[string]string
+		want     time.Duration
+	}{
+		{"latest", "/carbon-intensity/latest", nil, 5 * time.Minute},
+		{"forecast", "/carbon-intensity/forecast", nil, 60 * time.Minute},
+		{"past with old end", "/carbon-intensity/past", map[string]string{"end": old}, 0},
+		{"past-range with old end", "/power-breakdown/past-range", map[string]string{"end": old}, 0},
+		{"past with recent end", "/carbon-intensity/past", map[string]string{"end": recent}, 60 * time.Minute},
+		{"past with recent datetime", "/carbon-intensity/past", map[string]string{"datetime": recent}, 60 * time.Minute},
+		{"past with old datetime", "/carbon-intensity/past", map[string]string{"datetime": old}, 0},
+		{"past with no window param", "/carbon-intensity/past", nil, 60 * time.Minute},
+		{"past with unparseable end", "/carbon-intensity/past", map[string]string{"end": "not-a-time"}, 60 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ttlForEndpoint(tt.endpoint, tt.query); got != tt.want {
+				t.Errorf("ttlForEndpoint(%q, %v) = %v, want %v", tt.endpoint, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileCacheGetSet(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Fatalf("Get on empty cache found a value")
+	}
+
+	cache.Set("a", []byte("payload"), 0)
+	v, ok := cache.Get("a")
+	if !ok || string(v) != "payload" {
+		t.Fatalf("Get(a) = %q, %v, want \"payload\", true", v, ok)
+	}
+}
+
+func TestFileCacheTTLExpiry(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	cache.Set("a", []byte("payload"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Fatalf("Get(a) found an entry past its TTL")
+	}
+}
+
+func TestFileCacheZeroTTLNeverExpires(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	cache.Set("a", []byte("payload"), 0)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatalf("Get(a) expired an entry with ttl <= 0")
+	}
+}
+
+func TestFileCachePersistsAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+	first.Set("a", []byte("payload"), 0)
+
+	second, err := NewFileCache(dir)
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+	v, ok := second.Get("a")
+	if !ok || string(v) != "payload" {
+		t.Fatalf("Get(a) on a fresh FileCache over the same dir = %q, %v, want \"payload\", true", v, ok)
+	}
+}
@@ -1,28 +1,121 @@
 package electricitymap
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
 )
 
+const defaultBaseURL = "https://api.electricitymap.org/v3"
+const defaultTimeout = 10 * time.Second
+const providerName = "electricitymap"
+
 type electricityMap struct {
-	zoneKey string
-	url     string
+	zoneKey         string
+	url             string
+	httpClient      *http.Client
+	logger          Logger
+	retry           RetryConfig
+	cache           Cache
+	group           *singleflightGroup
+	metrics         *CacheMetrics
+	instrumentation carbonintensity.Instrumentation
+}
+
+// Option configures a client built by New.
+type Option func(*electricityMap)
+
+// WithBaseURL overrides the ElectricityMap API base URL, e.g. to point at a
+// staging environment or a test server.
+func WithBaseURL(url string) Option {
+	return func(e *electricityMap) { e.url = url }
+}
+
+// WithHTTPClient overrides the http.Client used for every request. Its
+// Timeout is respected; use WithTimeout instead if the client is otherwise
+// default.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *electricityMap) { e.httpClient = client }
+}
+
+// WithTimeout sets the per-request timeout on the client's http.Client.
+func WithTimeout(d time.Duration) Option {
+	return func(e *electricityMap) { e.httpClient.Timeout = d }
+}
+
+// WithLogger overrides the client's Logger. The default is a no-op, so
+// nothing is printed unless a Logger is supplied.
+func WithLogger(logger Logger) Option {
+	return func(e *electricityMap) { e.logger = logger }
+}
+
+// WithRetry overrides the exponential backoff policy used when the API
+// responds with 429 or 503.
+func WithRetry(cfg RetryConfig) Option {
+	return func(e *electricityMap) { e.retry = cfg }
 }
 
-func New(zoneKey string) electricityMap {
+// WithCache overrides the response cache. Pass nil to disable caching
+// entirely. The default is an in-memory LRUCache; use NewFileCache for a
+// cache that survives across separate CI job invocations.
+func WithCache(cache Cache) Option {
+	return func(e *electricityMap) { e.cache = cache }
+}
+
+// WithInstrumentation wires a carbonintensity.Instrumentation into the
+// client so every request's status/latency and every carbon intensity or
+// power breakdown reading is observed. The default is
+// carbonintensity.NopInstrumentation{}.
+func WithInstrumentation(instr carbonintensity.Instrumentation) Option {
+	return func(e *electricityMap) { e.instrumentation = instr }
+}
+
+const defaultCacheCapacity = 128
+
+// New builds an ElectricityMap client authenticated with the given API
+// token. By default it talks to the production API with a 10s timeout, a
+// no-op logger, three retries with exponential backoff on 429/503, a
+// 128-entry in-memory response cache, and no-op instrumentation.
+func New(zoneKey string, opts ...Option) electricityMap {
 	em := electricityMap{
-		zoneKey: zoneKey,
-		url:     "https://api.electricitymap.org/v3",
+		zoneKey:         zoneKey,
+		url:             defaultBaseURL,
+		httpClient:      &http.Client{Timeout: defaultTimeout},
+		logger:          NoopLogger{},
+		retry:           DefaultRetryConfig(),
+		cache:           NewLRUCache(defaultCacheCapacity),
+		group:           &singleflightGroup{},
+		metrics:         &CacheMetrics{},
+		instrumentation: carbonintensity.NopInstrumentation{},
+	}
+	for _, opt := range opts {
+		opt(&em)
 	}
 	return em
 }
 
+// startSpan starts an OpenTelemetry span named "electricitymap.<name>" for
+// one client method, so every provider call shows up in traces alongside
+// the pipeline step that triggered it.
+func (e electricityMap) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer("electricitymap").Start(ctx, "electricitymap."+name)
+}
+
+// CacheMetrics returns the client's cache hit/miss/coalesced counters.
+func (e electricityMap) CacheMetrics() *CacheMetrics {
+	return e.metrics
+}
+
 func httpQueryBuilder(zoneKey string, params TypAPIParams) (header map[string]string, query map[string]string) {
 	header = make(map[string]string)
 	query = make(map[string]string)
@@ -57,16 +150,16 @@ This endpoint returns all zones available if no auth-token is provided.
 
 If an auth-token is provided, it returns a list of zones and routes available with this token
 */
-func (e electricityMap) GetZones() (map[string]typZone, error) {
-	url := fmt.Sprintf("%v/zones", e.url)
+func (e electricityMap) GetZones(ctx context.Context) (map[string]typZone, error) {
+	ctx, span := e.startSpan(ctx, "GetZones")
+	defer span.End()
+
 	data := make(map[string]typZone)
-	header := make(map[string]string)
+	header := map[string]string{"auth-token": e.zoneKey}
 	query := make(map[string]string)
 
-	header["auth-token"] = e.zoneKey
-
-	fmt.Println("Getting Electricity Map Zones")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Zones")
+	err := e.get(ctx, "/zones", header, query, &data)
 	return data, err
 }
 
@@ -83,14 +176,46 @@ lon | Longitude (if querying with a geolocation)
 
 lat | Latitude (if querying with a geolocation)
 */
-func (e electricityMap) LiveCarbonIntensity(params TypAPIParams) (typCI, error) {
-	url := fmt.Sprintf("%v/carbon-intensity/latest", e.url)
+func (e electricityMap) LiveCarbonIntensity(ctx context.Context, params TypAPIParams) (typCI, error) {
+	ctx, span := e.startSpan(ctx, "LiveCarbonIntensity")
+	defer span.End()
+
 	var data typCI
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Live Carbon Intensity")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Live Carbon Intensity")
+	err := e.get(ctx, "/carbon-intensity/latest", header, query, &data)
+	if err == nil {
+		e.instrumentation.ObserveCarbonIntensity(data.Zone, float64(data.CarbonIntensity))
+	}
+	return data, err
+
+}
+
+/*
+This endpoint retrieves the carbon intensity (in gCO2eq/kWh) forecast for the next 24h of an area. It can either be queried by zone identifier or by geolocation. The resolution is 60 minutes.
+
+QUERY PARAMETERS
+
+Parameter | Description
+
+zone | A string representing the zone identifier
+
+lon | Longitude (if querying with a geolocation)
+
+lat | Latitude (if querying with a geolocation)
+*/
+func (e electricityMap) ForecastCarbonIntensity(ctx context.Context, params TypAPIParams) (typForecast, error) {
+	ctx, span := e.startSpan(ctx, "ForecastCarbonIntensity")
+	defer span.End()
+
+	var data typForecast
+
+	header, query := httpQueryBuilder(e.zoneKey, params)
+
+	e.logger.Debugf("Getting Electricity Map Carbon Intensity Forecast")
+	err := e.get(ctx, "/carbon-intensity/forecast", header, query, &data)
 	return data, err
 
 }
@@ -116,14 +241,19 @@ lon | Longitude (if querying with a geolocation)
 
 lat | Latitude (if querying with a geolocation)
 */
-func (e electricityMap) LivePowerBreakdown(params TypAPIParams) (typPB, error) {
-	url := fmt.Sprintf("%v/power-breakdown/latest", e.url)
+func (e electricityMap) LivePowerBreakdown(ctx context.Context, params TypAPIParams) (typPB, error) {
+	ctx, span := e.startSpan(ctx, "LivePowerBreakdown")
+	defer span.End()
+
 	var data typPB
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Live Power Breakdown")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Live Power Breakdown")
+	err := e.get(ctx, "/power-breakdown/latest", header, query, &data)
+	if err == nil {
+		e.instrumentation.ObservePowerBreakdown(data.Zone, float64(data.RenewablePercentage), float64(data.FossilFreePercentage))
+	}
 	return data, err
 
 }
@@ -141,14 +271,16 @@ lon | Longitude (if querying with a geolocation)
 
 lat | Latitude (if querying with a geolocation)
 */
-func (e electricityMap) RecentCarbonIntensity(params TypAPIParams) (typRecentCI, error) {
-	url := fmt.Sprintf("%v/carbon-intensity/history", e.url)
+func (e electricityMap) RecentCarbonIntensity(ctx context.Context, params TypAPIParams) (typRecentCI, error) {
+	ctx, span := e.startSpan(ctx, "RecentCarbonIntensity")
+	defer span.End()
+
 	var data typRecentCI
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Recent Carbon Intensity")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Recent Carbon Intensity")
+	err := e.get(ctx, "/carbon-intensity/history", header, query, &data)
 	return data, err
 
 }
@@ -166,14 +298,16 @@ lon | Longitude (if querying with a geolocation)
 
 lat | Latitude (if querying with a geolocation)
 */
-func (e electricityMap) RecentPowerBreakdown(params TypAPIParams) (typRecentPB, error) {
-	url := fmt.Sprintf("%v/power-consumption-breakdown/history", e.url)
+func (e electricityMap) RecentPowerBreakdown(ctx context.Context, params TypAPIParams) (typRecentPB, error) {
+	ctx, span := e.startSpan(ctx, "RecentPowerBreakdown")
+	defer span.End()
+
 	var data typRecentPB
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Recent Power Breakdown")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Recent Power Breakdown")
+	err := e.get(ctx, "/power-consumption-breakdown/history", header, query, &data)
 	return data, err
 
 }
@@ -195,14 +329,19 @@ datetime | datetime in ISO format
 
 estimationFallback | (optional) boolean (if estimated data should be included)
 */
-func (e electricityMap) PastCarbonIntensity(params TypAPIParams) (typCI, error) {
-	url := fmt.Sprintf("%v/carbon-intensity/past", e.url)
+func (e electricityMap) PastCarbonIntensity(ctx context.Context, params TypAPIParams) (typCI, error) {
+	ctx, span := e.startSpan(ctx, "PastCarbonIntensity")
+	defer span.End()
+
 	var data typCI
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Past Carbon Intensity")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Past Carbon Intensity")
+	err := e.get(ctx, "/carbon-intensity/past", header, query, &data)
+	if err == nil {
+		e.instrumentation.ObserveCarbonIntensity(data.Zone, float64(data.CarbonIntensity))
+	}
 	return data, err
 
 }
@@ -226,14 +365,16 @@ end | datetime in ISO format (excluded)
 
 estimationFallback | (optional) boolean (if estimated data should be included)
 */
-func (e electricityMap) PastCarbonIntensityRange(params TypAPIParams) (map[string][]typCI, error) {
-	url := fmt.Sprintf("%v/carbon-intensity/past-range", e.url)
-	var data = make(map[string][]typCI)
+func (e electricityMap) PastCarbonIntensityRange(ctx context.Context, params TypAPIParams) (typPastCIRange, error) {
+	ctx, span := e.startSpan(ctx, "PastCarbonIntensityRange")
+	defer span.End()
+
+	var data typPastCIRange
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Past Carbon Intensity Range")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Past Carbon Intensity Range")
+	err := e.get(ctx, "/carbon-intensity/past-range", header, query, &data)
 	return data, err
 
 }
@@ -255,14 +396,19 @@ datetime | datetime in ISO format
 
 estimationFallback | (optional) boolean (if estimated data should be included)
 */
-func (e electricityMap) PastPowerBreakdown(params TypAPIParams) (typPB, error) {
-	url := fmt.Sprintf("%v/power-breakdown/past", e.url)
+func (e electricityMap) PastPowerBreakdown(ctx context.Context, params TypAPIParams) (typPB, error) {
+	ctx, span := e.startSpan(ctx, "PastPowerBreakdown")
+	defer span.End()
+
 	var data typPB
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Past Power Breakdown")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Past Power Breakdown")
+	err := e.get(ctx, "/power-breakdown/past", header, query, &data)
+	if err == nil {
+		e.instrumentation.ObservePowerBreakdown(data.Zone, float64(data.RenewablePercentage), float64(data.FossilFreePercentage))
+	}
 	return data, err
 
 }
@@ -286,73 +432,133 @@ end | datetime in ISO format (excluded)
 
 estimationFallback | (optional) boolean (if estimated data should be included)
 */
-func (e electricityMap) PastPowerBreakdownRange(params TypAPIParams) (map[string][]typPB, error) {
-	url := fmt.Sprintf("%v/power-breakdown/past-range", e.url)
-	var data = make(map[string][]typPB)
+func (e electricityMap) PastPowerBreakdownRange(ctx context.Context, params TypAPIParams) (typPastPBRange, error) {
+	ctx, span := e.startSpan(ctx, "PastPowerBreakdownRange")
+	defer span.End()
+
+	var data typPastPBRange
 
 	header, query := httpQueryBuilder(e.zoneKey, params)
 
-	fmt.Println("Getting Electricity Map Past Power Breakdown Range")
-	err := httpGet(url, &data, header, query)
+	e.logger.Debugf("Getting Electricity Map Past Power Breakdown Range")
+	err := e.get(ctx, "/power-breakdown/past-range", header, query, &data)
 	return data, err
 
 }
 
-func httpGet(url string, data interface{}, header map[string]string, query map[string]string) error {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
-	if hasError(err) {
-		fmt.Println("http.NewRequest error")
-		fmt.Println(err.Error())
-		return err
-	}
+// get fetches endpoint (relative to e.url) into data, going through the
+// response cache and coalescing concurrent identical requests when caching
+// is enabled. Each endpoint's TTL follows its natural data resolution and,
+// for "past" endpoints, how old the requested window actually is; see
+// ttlForEndpoint.
+func (e electricityMap) get(ctx context.Context, endpoint string, header, query map[string]string, data interface{}) error {
+	fullURL := fmt.Sprintf("%v%v", e.url, endpoint)
 
-	// Add Headers
-	for k := range header {
-		// fmt.Printf("Adding header %v:%v\n", k, header[k])
-		req.Header.Add(k, header[k])
+	if e.cache == nil {
+		return e.httpGet(ctx, endpoint, fullURL, data, header, query)
 	}
 
-	// Get URL Query String
-	q := req.URL.Query()
+	key := cacheKey(endpoint, query)
+
+	raw, shared, err := e.group.Do(key, func() ([]byte, error) {
+		if cached, ok := e.cache.Get(key); ok {
+			e.metrics.recordHit()
+			return cached, nil
+		}
+		e.metrics.recordMiss()
 
+		var raw json.RawMessage
+		if err := e.httpGet(ctx, endpoint, fullURL, &raw, header, query); err != nil {
+			return nil, err
+		}
+		e.cache.Set(key, raw, ttlForEndpoint(endpoint, query))
+		return raw, nil
+	})
+	if err != nil {
+		return err
+	}
+	if shared {
+		e.metrics.recordCoalesced()
+	}
+	return json.Unmarshal(raw, data)
+}
+
+// cacheKey builds a deterministic cache key from endpoint and query, e.g.
+// "/carbon-intensity/latest|zone=DE".
+func cacheKey(endpoint string, query map[string]string) string {
+	keys := make([]string, 0, len(query))
 	for k := range query {
-		q.Add(k, query[k])
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(endpoint)
+	for _, k := range keys {
+		b.WriteByte('|')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(query[k])
 	}
+	return b.String()
+}
 
-	// Add query string to URL
-	req.URL.RawQuery = q.Encode()
+// httpGet performs a GET request against url, decoding a 200 response
+// straight from the body stream into data. On 429/503 it retries with
+// exponential backoff (honoring Retry-After) up to e.retry.MaxRetries
+// times; any other non-200 status is translated into one of this package's
+// typed errors by classifyStatus. endpoint (e.g. "/carbon-intensity/latest")
+// and the final HTTP status are reported to e.instrumentation, keyed apart
+// from url so cache-busting query strings don't fragment the metric labels.
+func (e electricityMap) httpGet(ctx context.Context, endpoint, url string, data interface{}, header map[string]string, query map[string]string) error {
+	start := time.Now()
+	status := 0
+	defer func() {
+		e.instrumentation.ObserveRequest(providerName, endpoint, status, time.Since(start))
+	}()
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("electricitymap: building request: %w", err)
+		}
 
-	// fmt.Println(req.URL)
-	response, err := client.Do(req)
-	if hasError(err) {
-		fmt.Println("client.Do error")
-		fmt.Println(err.Error())
-		return err
-	}
+		for k := range header {
+			req.Header.Add(k, header[k])
+		}
 
-	if response.StatusCode == 200 {
-		responseData, err := ioutil.ReadAll(response.Body)
-		if hasError(err) {
-			fmt.Println("ioutil.ReadAll error")
-			fmt.Println(err.Error())
-			return err
+		q := req.URL.Query()
+		for k := range query {
+			q.Add(k, query[k])
 		}
+		req.URL.RawQuery = q.Encode()
 
-		json.Unmarshal(responseData, &data)
-		return nil //no error
-	} else {
-		err = errors.New(response.Status)
-		return err
-	}
-}
+		response, err := e.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrTransient, err)
+		}
+		status = response.StatusCode
 
-func hasError(err error) bool {
-	if err != nil {
-		log.Fatal(err)
-		return true
+		if response.StatusCode == http.StatusOK {
+			defer response.Body.Close()
+			return json.NewDecoder(response.Body).Decode(data)
+		}
+
+		var apiErr apiErrorEnvelope
+		json.NewDecoder(response.Body).Decode(&apiErr)
+		response.Body.Close()
+
+		retryable := response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable
+		if retryable && attempt < e.retry.MaxRetries {
+			delay := e.retry.nextDelay(attempt, parseRetryAfter(response.Header.Get("Retry-After")))
+			e.logger.Infof("electricitymap: got %d, retrying in %v (attempt %d/%d)", response.StatusCode, delay, attempt+1, e.retry.MaxRetries)
+			time.Sleep(delay)
+			continue
+		}
+
+		e.logger.Errorf("electricitymap: request failed with status %v", response.Status)
+		return classifyStatus(response.StatusCode, apiErr)
 	}
-	return false
 }
 
 type TypAPIParams struct {
@@ -374,44 +580,74 @@ type typCI struct {
 }
 
 type typPB struct {
-	Zone                      string                       `json:"zone"`
-	Datetime                  string                       `json:"datetime"`
-	PowerProductionBreakdown  typPowerProductionBreakdown  `json:"powerProductionBreakdown"`
-	PowerProductionTotal      int                          `json:"powerProductionTotal"`
-	PowerConsumptionBreakdown typPowerConsumptionBreakdown `json:"powerConsumptionBreakdown"`
-	PowerConsumptionTotal     int                          `json:"powerConsumptionTotal"`
-	PowerImportBreakdown      typPowerImpExpBreakdown      `json:"powerImportBreakdown"`
-	PowerImportTotal          int                          `json:"powerImportTotal"`
-	PowerExportBreakdown      typPowerImpExpBreakdown      `json:"powerExportBreakdown"`
-	PowerExportTotal          int                          `json:"powerExportTotal"`
-	FossilFreePercentage      int                          `json:"fossilFreePercentage"`
-	RenewablePercentage       int                          `json:"renewablePercentage"`
-	UpdatedAt                 string                       `json:"updatedAt"`
-	CreatedAt                 string                       `json:"createdAt"`
-}
-
-type typPowerConsumptionBreakdown struct {
-	BatteryDischarge string // battery discharge `json:"batteryDischarge"`
-	Biomass          int    `json:"biomass"`
-	Coal             int    `json:"coal"`
-	Gas              int    `json:"gas"`
-	Geothermal       int    `json:"geothermal"`
-	Hydro            int    `json:"hydro"`
-	HydroDischarge   int    //hydro discharge `json:"hydroDischarge"`
-	Nuclear          int    `json:"nuclear"`
-	Oil              int    `json:"oil"`
-	Solar            int    `json:"solar"`
-	Unknown          int    `json:"unknown"`
-	Wind             int    `json:"wind"`
-}
-
-type typPowerImpExpBreakdown struct {
-	DE     int `json:"DE"`
-	DK_DK1 int //DK-DK1 `json:"DK_DK1"`
-	SE     int `json:"SE"`
-}
-
-type typPowerProductionBreakdown struct {
+	Zone                      string                     `json:"zone"`
+	Datetime                  string                     `json:"datetime"`
+	PowerProductionBreakdown  PowerProductionBreakdown   `json:"powerProductionBreakdown"`
+	PowerProductionTotal      int                        `json:"powerProductionTotal"`
+	PowerConsumptionBreakdown PowerConsumptionBreakdown  `json:"powerConsumptionBreakdown"`
+	PowerConsumptionTotal     int                        `json:"powerConsumptionTotal"`
+	PowerImportBreakdown      PowerImpExpBreakdown       `json:"powerImportBreakdown"`
+	PowerImportTotal          int                        `json:"powerImportTotal"`
+	PowerExportBreakdown      PowerImpExpBreakdown       `json:"powerExportBreakdown"`
+	PowerExportTotal          int                        `json:"powerExportTotal"`
+	FossilFreePercentage      int                        `json:"fossilFreePercentage"`
+	RenewablePercentage       int                        `json:"renewablePercentage"`
+	UpdatedAt                 string                     `json:"updatedAt"`
+	CreatedAt                 string                     `json:"createdAt"`
+}
+
+// PowerConsumptionBreakdown is the physical origin of a zone's power
+// consumption, broken down by production type (in MW).
+type PowerConsumptionBreakdown struct {
+	BatteryDischarge int `json:"batteryDischarge"`
+	Biomass          int `json:"biomass"`
+	Coal             int `json:"coal"`
+	Gas              int `json:"gas"`
+	Geothermal       int `json:"geothermal"`
+	Hydro            int `json:"hydro"`
+	HydroDischarge   int `json:"hydroDischarge"`
+	Nuclear          int `json:"nuclear"`
+	Oil              int `json:"oil"`
+	Solar            int `json:"solar"`
+	Unknown          int `json:"unknown"`
+	Wind             int `json:"wind"`
+}
+
+// Fuels returns every entry of the breakdown keyed by fuel name, zero values
+// included, for callers that want to iterate the mix rather than name every
+// field.
+func (b PowerConsumptionBreakdown) Fuels() map[string]int {
+	return map[string]int{
+		"batteryDischarge": b.BatteryDischarge,
+		"biomass":          b.Biomass,
+		"coal":             b.Coal,
+		"gas":              b.Gas,
+		"geothermal":       b.Geothermal,
+		"hydro":            b.Hydro,
+		"hydroDischarge":   b.HydroDischarge,
+		"nuclear":          b.Nuclear,
+		"oil":              b.Oil,
+		"solar":            b.Solar,
+		"unknown":          b.Unknown,
+		"wind":             b.Wind,
+	}
+}
+
+// PowerImpExpBreakdown is a zone's physical electricity flow to or from its
+// neighbors, keyed by ElectricityMap zone/subzone identifier (e.g. "DE",
+// "DK-DK1", "US-CAL-CISO"). It's a map rather than fixed fields because the
+// set of neighbors differs per zone and grows as ElectricityMap adds zones.
+type PowerImpExpBreakdown map[string]int
+
+// TotalTo returns the flow (in MW) to or from the given neighboring zone,
+// or 0 if the breakdown has no entry for it.
+func (b PowerImpExpBreakdown) TotalTo(zone string) int {
+	return b[zone]
+}
+
+// PowerProductionBreakdown is the physical origin of a zone's power
+// production, broken down by production type (in MW).
+type PowerProductionBreakdown struct {
 	Biomass    int `json:"biomass"`
 	Coal       int `json:"coal"`
 	Gas        int `json:"gas"`
@@ -424,6 +660,24 @@ type typPowerProductionBreakdown struct {
 	Wind       int `json:"wind"`
 }
 
+// Fuels returns every entry of the breakdown keyed by fuel name, zero values
+// included, for callers that want to iterate the mix rather than name every
+// field.
+func (b PowerProductionBreakdown) Fuels() map[string]int {
+	return map[string]int{
+		"biomass":    b.Biomass,
+		"coal":       b.Coal,
+		"gas":        b.Gas,
+		"geothermal": b.Geothermal,
+		"hydro":      b.Hydro,
+		"nuclear":    b.Nuclear,
+		"oil":        b.Oil,
+		"solar":      b.Solar,
+		"unknown":    b.Unknown,
+		"wind":       b.Wind,
+	}
+}
+
 type typZone struct {
 	CountryName string   `json:"countryName"`
 	ZoneName    string   `json:"zoneName"`
@@ -440,19 +694,44 @@ type typRecentCI struct {
 	} `json:"history"`
 }
 
+// typPastCIRange is the payload shape of /carbon-intensity/past-range: a
+// single object carrying the queried zone and its history, not a map keyed
+// by zone.
+type typPastCIRange struct {
+	Zone    string  `json:"zone"`
+	History []typCI `json:"history"`
+}
+
+// typPastPBRange is the payload shape of /power-breakdown/past-range: a
+// single object carrying the queried zone and its history, not a map keyed
+// by zone.
+type typPastPBRange struct {
+	Zone    string  `json:"zone"`
+	History []typPB `json:"history"`
+}
+
+type typForecast struct {
+	Zone     string `json:"zone"`
+	Forecast []struct {
+		CarbonIntensity int    `json:"carbonIntensity"`
+		Datetime        string `json:"datetime"`
+	} `json:"forecast"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
 type typRecentPB struct {
 	Zone    string `json:"zone"`
 	History []struct {
-		Datetime                  string                       `json:"datetime"`
-		FossilFreePercentage      string                       `json:"fossilFreePercentage"`
-		PowerConsumptionBreakdown typPowerConsumptionBreakdown `json:"powerConsumptionBreakdown"`
-		PowerConsumptionTotal     int                          `json:"powerConsumptionTotal"`
-		PowerImportBreakdown      typPowerImpExpBreakdown      `json:"powerImportBreakdown"`
-		PowerImportTotal          int                          `json:"powerImportTotal"`
-		PowerExportBreakdown      typPowerImpExpBreakdown      `json:"powerExportBreakdown"`
-		PowerExportTotal          int                          `json:"powerExportTotal"`
-		PowerProductionBreakdown  typPowerProductionBreakdown  `json:"powerProductionBreakdown"`
-		PowerProductionTotal      int                          `json:"powerProductionTotal"`
-		RenewablePercentage       int                          `json:"renewablePercentage"`
+		Datetime                  string                    `json:"datetime"`
+		FossilFreePercentage      string                    `json:"fossilFreePercentage"`
+		PowerConsumptionBreakdown PowerConsumptionBreakdown `json:"powerConsumptionBreakdown"`
+		PowerConsumptionTotal     int                       `json:"powerConsumptionTotal"`
+		PowerImportBreakdown      PowerImpExpBreakdown      `json:"powerImportBreakdown"`
+		PowerImportTotal          int                       `json:"powerImportTotal"`
+		PowerExportBreakdown      PowerImpExpBreakdown      `json:"powerExportBreakdown"`
+		PowerExportTotal          int                       `json:"powerExportTotal"`
+		PowerProductionBreakdown  PowerProductionBreakdown  `json:"powerProductionBreakdown"`
+		PowerProductionTotal      int                       `json:"powerProductionTotal"`
+		RenewablePercentage       int                       `json:"renewablePercentage"`
 	} `json:"history"`
-}
\ No newline at end of file
+}
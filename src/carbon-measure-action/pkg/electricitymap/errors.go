@@ -0,0 +1,54 @@
+package electricitymap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors returned by this package's API calls. Callers should use
+// errors.Is to test for them, since they're always wrapped with additional
+// context from the ElectricityMap error envelope.
+var (
+	// ErrUnauthorized is returned on 401/403: the API token is missing or
+	// doesn't have access to the requested zone.
+	ErrUnauthorized = errors.New("electricitymap: unauthorized")
+	// ErrZoneNotFound is returned on 404: the requested zone doesn't exist.
+	ErrZoneNotFound = errors.New("electricitymap: zone not found")
+	// ErrRateLimited is returned on 429: the API token's quota is exhausted.
+	ErrRateLimited = errors.New("electricitymap: rate limited")
+	// ErrTransient is returned on 5xx responses and network-level failures
+	// that are generally safe to retry.
+	ErrTransient = errors.New("electricitymap: transient upstream error")
+)
+
+// apiErrorEnvelope is the JSON body ElectricityMap returns on non-2xx
+// responses.
+type apiErrorEnvelope struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+func (e apiErrorEnvelope) detail() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return e.Error
+}
+
+// classifyStatus maps an HTTP status code and decoded error envelope to one
+// of this package's sentinel errors.
+func classifyStatus(status int, body apiErrorEnvelope) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", ErrUnauthorized, body.detail())
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrZoneNotFound, body.detail())
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", ErrRateLimited, body.detail())
+	}
+	if status >= 500 {
+		return fmt.Errorf("%w: %s", ErrTransient, body.detail())
+	}
+	return fmt.Errorf("electricitymap: unexpected status %d: %s", status, body.detail())
+}
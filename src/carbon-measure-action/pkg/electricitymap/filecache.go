@@ -0,0 +1,68 @@
+package electricitymap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FileCache is a Cache backed by a directory on disk, so cached responses
+// survive across separate CI job invocations rather than just within one
+// process. Each entry is stored as "<expiresAtUnixNano>\n<payload>" in a
+// file named after the SHA-256 of its key.
+type FileCache struct {
+	dir string
+}
+
+// NewFileCache builds a FileCache rooted at dir, creating it if necessary.
+func NewFileCache(dir string) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("electricitymap: creating cache dir: %w", err)
+	}
+	return &FileCache{dir: dir}, nil
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Cache.
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	raw, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	header, payload, found := strings.Cut(string(raw), "\n")
+	if !found {
+		return nil, false
+	}
+
+	expiresAtUnixNano, err := strconv.ParseInt(header, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	if expiresAtUnixNano != 0 && time.Now().UnixNano() > expiresAtUnixNano {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+
+	return []byte(payload), true
+}
+
+// Set implements Cache. ttl <= 0 means the entry never expires.
+func (c *FileCache) Set(key string, value []byte, ttl time.Duration) {
+	var expiresAtUnixNano int64
+	if ttl > 0 {
+		expiresAtUnixNano = time.Now().Add(ttl).UnixNano()
+	}
+
+	contents := append([]byte(strconv.FormatInt(expiresAtUnixNano, 10)+"\n"), value...)
+	_ = os.WriteFile(c.path(key), contents, 0o644)
+}
@@ -0,0 +1,67 @@
+package electricitymap
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestPowerBreakdownGoldenFiles decodes synthetic but API-shaped
+// power-breakdown payloads for a dozen ElectricityMap zones/subzones,
+// including neighbors outside the old hard-coded DE/DK-DK1/SE set, to guard
+// against PowerImpExpBreakdown silently dropping flows again. The fixtures
+// are hand-written, not captured from the live API, so they won't catch a
+// divergence in the API's actual payload shape (see the golden files'
+// README for that caveat).
+func TestPowerBreakdownGoldenFiles(t *testing.T) {
+	files, err := filepath.Glob("testdata/powerbreakdown/*.json")
+	if err != nil {
+		t.Fatalf("globbing testdata: %v", err)
+	}
+	if len(files) < 12 {
+		t.Fatalf("expected at least 12 golden files, got %d", len(files))
+	}
+
+	for _, file := range files {
+		zone := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(zone, func(t *testing.T) {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("reading %s: %v", file, err)
+			}
+
+			var data typPB
+			if err := json.Unmarshal(raw, &data); err != nil {
+				t.Fatalf("decoding %s: %v", file, err)
+			}
+
+			if data.Zone != zone {
+				t.Fatalf("Zone = %q, want %q", data.Zone, zone)
+			}
+
+			for neighbor, want := range data.PowerImportBreakdown {
+				if got := data.PowerImportBreakdown.TotalTo(neighbor); got != want {
+					t.Errorf("PowerImportBreakdown.TotalTo(%q) = %d, want %d", neighbor, got, want)
+				}
+			}
+			if got := data.PowerImportBreakdown.TotalTo("ZZ-not-a-neighbor"); got != 0 {
+				t.Errorf("TotalTo of unknown neighbor = %d, want 0", got)
+			}
+
+			consumptionFuels := data.PowerConsumptionBreakdown.Fuels()
+			if consumptionFuels["batteryDischarge"] != data.PowerConsumptionBreakdown.BatteryDischarge {
+				t.Errorf("Fuels()[batteryDischarge] = %d, want %d", consumptionFuels["batteryDischarge"], data.PowerConsumptionBreakdown.BatteryDischarge)
+			}
+			if consumptionFuels["hydroDischarge"] != data.PowerConsumptionBreakdown.HydroDischarge {
+				t.Errorf("Fuels()[hydroDischarge] = %d, want %d", consumptionFuels["hydroDischarge"], data.PowerConsumptionBreakdown.HydroDischarge)
+			}
+
+			productionFuels := data.PowerProductionBreakdown.Fuels()
+			if productionFuels["wind"] != data.PowerProductionBreakdown.Wind {
+				t.Errorf("Fuels()[wind] = %d, want %d", productionFuels["wind"], data.PowerProductionBreakdown.Wind)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package electricitymap
+
+import "log"
+
+// Logger is a minimal levelled logging interface, modeled on go-kit/log, so
+// callers can plug in their own structured logger instead of this package
+// writing to stdout.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// NoopLogger discards everything. It's the default Logger for New, so
+// embedding this package stays silent unless a Logger is supplied.
+type NoopLogger struct{}
+
+func (NoopLogger) Debugf(format string, args ...interface{}) {}
+func (NoopLogger) Infof(format string, args ...interface{})  {}
+func (NoopLogger) Errorf(format string, args ...interface{}) {}
+
+// StdLogger adapts the standard library's log.Logger to the Logger
+// interface, prefixing each line with its level.
+type StdLogger struct {
+	*log.Logger
+}
+
+func (l StdLogger) Debugf(format string, args ...interface{}) {
+	l.Printf("DEBUG "+format, args...)
+}
+
+func (l StdLogger) Infof(format string, args ...interface{}) {
+	l.Printf("INFO "+format, args...)
+}
+
+func (l StdLogger) Errorf(format string, args ...interface{}) {
+	l.Printf("ERROR "+format, args...)
+}
@@ -0,0 +1,46 @@
+package electricitymap
+
+import "sync"
+
+// singleflightGroup coalesces concurrent callers requesting the same key
+// into a single in-flight call, so a burst of parallel pipeline steps only
+// performs one upstream fetch.
+type singleflightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*singleflightCall
+}
+
+type singleflightCall struct {
+	wg  sync.WaitGroup
+	val []byte
+	err error
+}
+
+// Do executes fn for key, or waits for an identical in-flight call to
+// finish and reuses its result. shared reports whether the result came from
+// such an in-flight call rather than this invocation of fn.
+func (g *singleflightGroup) Do(key string, fn func() ([]byte, error)) (val []byte, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*singleflightCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.val, true, c.err
+	}
+
+	c := new(singleflightCall)
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.val, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return c.val, false, c.err
+}
@@ -0,0 +1,116 @@
+package electricitymap
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSingleflightGroupCoalescesConcurrentCallers(t *testing.T) {
+	g := &singleflightGroup{}
+
+	var calls int32
+	start := make(chan struct{})
+	const callers = 10
+
+	var wg sync.WaitGroup
+	results := make([][]byte, callers)
+	shares := make([]bool, callers)
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		i := i
+		go func() {
+			defer wg.Done()
+			<-start
+			val, shared, err := g.Do("key", func() ([]byte, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(20 * time.Millisecond) // hold the call open so concurrent callers coalesce onto it
+				return []byte("value"), nil
+			})
+			if err != nil {
+				t.Errorf("Do: %v", err)
+			}
+			results[i] = val
+			shares[i] = shared
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("fn called %d times, want exactly 1", got)
+	}
+
+	sharedCount := 0
+	for i, v := range results {
+		if string(v) != "value" {
+			t.Errorf("results[%d] = %q, want \"value\"", i, v)
+		}
+		if shares[i] {
+			sharedCount++
+		}
+	}
+	if sharedCount != callers-1 {
+		t.Errorf("%d callers reported shared=true, want %d (all but the one that actually ran fn)", sharedCount, callers-1)
+	}
+}
+
+func TestSingleflightGroupSeparateKeysRunIndependently(t *testing.T) {
+	g := &singleflightGroup{}
+
+	var calls int32
+	_, _, err := g.Do("a", func() ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return []byte("a"), nil
+	})
+	if err != nil {
+		t.Fatalf("Do(a): %v", err)
+	}
+	_, _, err = g.Do("b", func() ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return []byte("b"), nil
+	})
+	if err != nil {
+		t.Fatalf("Do(b): %v", err)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("fn called %d times across distinct keys, want 2", got)
+	}
+}
+
+func TestSingleflightGroupSubsequentCallsRunAgain(t *testing.T) {
+	g := &singleflightGroup{}
+
+	var calls int32
+	for i := 0; i < 3; i++ {
+		_, shared, err := g.Do("key", func() ([]byte, error) {
+			atomic.AddInt32(&calls, 1)
+			return []byte("value"), nil
+		})
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		if shared {
+			t.Errorf("call %d reported shared=true, want false once the prior call has completed", i)
+		}
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("fn called %d times across 3 sequential calls, want 3 (no stale coalescing)", got)
+	}
+}
+
+func TestSingleflightGroupPropagatesError(t *testing.T) {
+	g := &singleflightGroup{}
+
+	wantErr := errors.New("boom")
+	_, _, err := g.Do("key", func() ([]byte, error) {
+		return nil, wantErr
+	})
+	if err != wantErr {
+		t.Errorf("Do error = %v, want %v", err, wantErr)
+	}
+}
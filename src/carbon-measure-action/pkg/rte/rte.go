@@ -0,0 +1,129 @@
+// Package rte is a thin client for RTE's eCO2mix open data API
+// (https://www.rte-france.com/eco2mix), adapted to the
+// carbonintensity.Provider interface. RTE only covers metropolitan France,
+// so loc.Zone is ignored; it is accepted for interface compatibility.
+package rte
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+)
+
+const defaultBaseURL = "https://digital.iservices.rte-france.com"
+
+// Client talks to the RTE eCO2mix "consumption" API, which reports
+// short-term CO2 intensity of consumed electricity for France.
+type Client struct {
+	baseURL     string
+	accessToken string
+	httpClient  *http.Client
+}
+
+// New builds a Client authenticated with an RTE API Portal OAuth2 access
+// token.
+func New(accessToken string) *Client {
+	return &Client{baseURL: defaultBaseURL, accessToken: accessToken, httpClient: &http.Client{}}
+}
+
+// Name identifies this Provider for logging and metric labels.
+func (c *Client) Name() string { return "rte-eco2mix" }
+
+type shortTermResponse struct {
+	ShortTerm []struct {
+		Type        string `json:"type"`
+		StartDate   string `json:"start_date"`
+		EndDate     string `json:"end_date"`
+		UpdatedDate string `json:"updated_date"`
+		Values      []struct {
+			Start string  `json:"start_date"`
+			End   string  `json:"end_date"`
+			Value float64 `json:"value"`
+		} `json:"values"`
+	} `json:"short_term"`
+}
+
+// LiveIntensity returns the most recent CO2 intensity sample reported by
+// eCO2mix.
+func (c *Client) LiveIntensity(ctx context.Context, loc carbonintensity.Location) (carbonintensity.Intensity, error) {
+	now := time.Now().UTC()
+	samples, err := c.shortTerm(ctx, now.Add(-2*time.Hour), now)
+	if err != nil {
+		return carbonintensity.Intensity{}, err
+	}
+	if len(samples) == 0 {
+		return carbonintensity.Intensity{}, fmt.Errorf("rte: no recent CO2 intensity samples")
+	}
+
+	latest := samples[len(samples)-1]
+	return carbonintensity.Intensity{
+		Zone:            "FR",
+		CarbonIntensity: latest.CarbonIntensity,
+		Datetime:        latest.Datetime,
+		UpdatedAt:       latest.UpdatedAt,
+	}, nil
+}
+
+// Forecast is not published by eCO2mix's consumption API; RTE only exposes
+// realised CO2 intensity, so this always returns an error.
+func (c *Client) Forecast(ctx context.Context, loc carbonintensity.Location, horizon time.Duration) ([]carbonintensity.ForecastPoint, error) {
+	return nil, fmt.Errorf("rte: carbon intensity forecasts are not available")
+}
+
+// PastRange returns realised CO2 intensity samples between start and end.
+func (c *Client) PastRange(ctx context.Context, loc carbonintensity.Location, start, end time.Time) ([]carbonintensity.Intensity, error) {
+	return c.shortTerm(ctx, start, end)
+}
+
+func (c *Client) shortTerm(ctx context.Context, start, end time.Time) ([]carbonintensity.Intensity, error) {
+	u := fmt.Sprintf("%v/open_api/consumption/v1/short_term", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+
+	q := url.Values{}
+	q.Set("type", "CO2")
+	q.Set("start_date", start.Format(time.RFC3339))
+	q.Set("end_date", end.Format(time.RFC3339))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rte: %v", resp.Status)
+	}
+
+	var data shortTermResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	var out []carbonintensity.Intensity
+	for _, series := range data.ShortTerm {
+		updated, _ := time.Parse(time.RFC3339, series.UpdatedDate)
+		for _, v := range series.Values {
+			dt, err := time.Parse(time.RFC3339, v.Start)
+			if err != nil {
+				continue
+			}
+			out = append(out, carbonintensity.Intensity{
+				Zone:            "FR",
+				CarbonIntensity: v.Value,
+				Datetime:        dt,
+				UpdatedAt:       updated,
+			})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,157 @@
+// Package watttime is a thin client for the WattTime v3 API
+// (https://www.watttime.org/api-documentation/), adapted to the
+// carbonintensity.Provider interface.
+package watttime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Green-Software-Foundation/Carbon_CI_Pipeline_Tooling/src/carbon-measure-action/pkg/carbonintensity"
+)
+
+const defaultBaseURL = "https://api.watttime.org/v3"
+
+// Client talks to the WattTime v3 API using a pre-obtained bearer token
+// (see WattTime's /login endpoint for how to mint one).
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// New builds a Client authenticated with a WattTime API bearer token.
+func New(token string) *Client {
+	return &Client{baseURL: defaultBaseURL, token: token, httpClient: &http.Client{}}
+}
+
+// Name identifies this Provider for logging and metric labels.
+func (c *Client) Name() string { return "watttime" }
+
+type signalIndexResponse struct {
+	Data []struct {
+		Point struct {
+			Time string `json:"time"`
+		} `json:"point"`
+		Value int `json:"value"`
+	} `json:"data"`
+	Meta struct {
+		Region     string `json:"region"`
+		SignalType string `json:"signal_type"`
+	} `json:"meta"`
+}
+
+// LiveIntensity calls GET /v3/signal-index for loc's coordinates (WattTime
+// indexes by lat/lon, not zone key) and returns the latest 0-100 "marginal
+// operating emissions" signal, which WattTime also reports in gCO2/kWh via
+// the signal_type=co2_moer query below.
+func (c *Client) LiveIntensity(ctx context.Context, loc carbonintensity.Location) (carbonintensity.Intensity, error) {
+	q := url.Values{}
+	q.Set("latitude", loc.Lat)
+	q.Set("longitude", loc.Lon)
+	q.Set("signal_type", "co2_moer")
+
+	var data signalIndexResponse
+	if err := c.get(ctx, "/signal-index", q, &data); err != nil {
+		return carbonintensity.Intensity{}, err
+	}
+	if len(data.Data) == 0 {
+		return carbonintensity.Intensity{}, fmt.Errorf("watttime: no signal data for location")
+	}
+
+	latest := data.Data[len(data.Data)-1]
+	dt, _ := time.Parse(time.RFC3339, latest.Point.Time)
+	return carbonintensity.Intensity{
+		Zone:            data.Meta.Region,
+		CarbonIntensity: float64(latest.Value),
+		Datetime:        dt,
+		UpdatedAt:       dt,
+	}, nil
+}
+
+type forecastResponse struct {
+	Data []struct {
+		Point struct {
+			Time string `json:"time"`
+		} `json:"point"`
+		Value float64 `json:"value"`
+	} `json:"data"`
+}
+
+// Forecast calls GET /v3/forecast for loc's coordinates.
+func (c *Client) Forecast(ctx context.Context, loc carbonintensity.Location, horizon time.Duration) ([]carbonintensity.ForecastPoint, error) {
+	q := url.Values{}
+	q.Set("latitude", loc.Lat)
+	q.Set("longitude", loc.Lon)
+	q.Set("signal_type", "co2_moer")
+	q.Set("horizon_hours", fmt.Sprintf("%d", int(horizon.Hours())))
+
+	var data forecastResponse
+	if err := c.get(ctx, "/forecast", q, &data); err != nil {
+		return nil, err
+	}
+
+	out := make([]carbonintensity.ForecastPoint, 0, len(data.Data))
+	for _, d := range data.Data {
+		dt, err := time.Parse(time.RFC3339, d.Point.Time)
+		if err != nil {
+			continue
+		}
+		out = append(out, carbonintensity.ForecastPoint{Datetime: dt, CarbonIntensity: d.Value})
+	}
+	return out, nil
+}
+
+// PastRange calls GET /v3/historical for loc's coordinates.
+func (c *Client) PastRange(ctx context.Context, loc carbonintensity.Location, start, end time.Time) ([]carbonintensity.Intensity, error) {
+	q := url.Values{}
+	q.Set("latitude", loc.Lat)
+	q.Set("longitude", loc.Lon)
+	q.Set("signal_type", "co2_moer")
+	q.Set("start", start.UTC().Format(time.RFC3339))
+	q.Set("end", end.UTC().Format(time.RFC3339))
+
+	var data signalIndexResponse
+	if err := c.get(ctx, "/historical", q, &data); err != nil {
+		return nil, err
+	}
+
+	out := make([]carbonintensity.Intensity, 0, len(data.Data))
+	for _, d := range data.Data {
+		dt, err := time.Parse(time.RFC3339, d.Point.Time)
+		if err != nil {
+			continue
+		}
+		out = append(out, carbonintensity.Intensity{
+			Zone:            data.Meta.Region,
+			CarbonIntensity: float64(d.Value),
+			Datetime:        dt,
+			UpdatedAt:       dt,
+		})
+	}
+	return out, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := fmt.Sprintf("%v%v?%v", c.baseURL, path, query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("watttime: %v", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}